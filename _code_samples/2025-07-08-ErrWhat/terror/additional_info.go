@@ -1,7 +1,7 @@
 package terror
 
 import (
-	"slices"
+	"sync"
 )
 
 type AdditionalInfo interface {
@@ -11,48 +11,76 @@ type AdditionalInfo interface {
 
 type AdditionalInfos []AdditionalInfo
 
-// Flatten removes duplicate keys from the AdditionalInfos slice.
-// It keeps the last occurrence of each key based on the order in the slice.
-func (instance AdditionalInfos) Flatten() (flattened AdditionalInfos) {
-	firstIndexOfKey := make(map[string]int)
-	lastIndexOfKey := make(map[string]int)
-	for i, info := range instance {
-		key := info.GetKey()
-		lastIndexOfKey[key] = i
-		_, exists := firstIndexOfKey[key]
-		if !exists {
-			firstIndexOfKey[key] = i
+// orderedInfos is a reusable ordered map of AdditionalInfo keyed on GetKey. Setting an existing key
+// replaces its value but keeps the position of the first occurrence, which is the ordering Flatten
+// has always produced.
+type orderedInfos struct {
+	indexOfKey map[string]int
+	infos      AdditionalInfos
+}
+
+var orderedInfosPool = sync.Pool{
+	New: func() any {
+		return &orderedInfos{
+			indexOfKey: make(map[string]int),
+			infos:      make(AdditionalInfos, 0, 16),
 		}
-	}
+	},
+}
 
-	type tuple struct {
-		index int
-		value AdditionalInfo
-	}
-	tuples := make([]tuple, 0, len(firstIndexOfKey))
-	for key, firstIndex := range firstIndexOfKey {
-		tuples = append(tuples, tuple{
-			index: firstIndex,
-			value: instance[lastIndexOfKey[key]],
-		})
+func getOrderedInfos() (instance *orderedInfos) {
+	return orderedInfosPool.Get().(*orderedInfos)
+}
+
+func putOrderedInfos(instance *orderedInfos) {
+	clear(instance.indexOfKey)
+	clear(instance.infos)
+	instance.infos = instance.infos[:0]
+	orderedInfosPool.Put(instance)
+}
+
+func (instance *orderedInfos) set(info AdditionalInfo) {
+	key := info.GetKey()
+	index, exists := instance.indexOfKey[key]
+	if exists {
+		instance.infos[index] = info
+		return
 	}
-	slices.SortFunc(tuples, func(a, b tuple) int {
-		return a.index - b.index
-	})
 
-	flattened = make(AdditionalInfos, 0, len(instance))
-	for _, tuple := range tuples {
-		flattened = append(flattened, tuple.value)
+	instance.indexOfKey[key] = len(instance.infos)
+	instance.infos = append(instance.infos, info)
+}
+
+func (instance *orderedInfos) setAll(infos AdditionalInfos) {
+	for _, info := range infos {
+		instance.set(info)
 	}
+}
+
+// toAdditionalInfos copies the merged infos out, so the ordered map can be returned to the pool
+func (instance *orderedInfos) toAdditionalInfos() (flattened AdditionalInfos) {
+	flattened = make(AdditionalInfos, len(instance.infos))
+	copy(flattened, instance.infos)
 
 	return flattened
 }
 
+// Flatten removes duplicate keys from the AdditionalInfos slice.
+// It keeps the last occurrence of each key based on the order in the slice.
+func (instance AdditionalInfos) Flatten() (flattened AdditionalInfos) {
+	merged := getOrderedInfos()
+	defer putOrderedInfos(merged)
+
+	merged.setAll(instance)
+
+	return merged.toAdditionalInfos()
+}
+
 // ToJSON converts a slice of AdditionalInfo into a map where each info's key maps to its value.
 // If multiple info entries have the same key, the last one's value will be used in the resulting map.
 // Returns a map[string]any containing all key-value pairs from the AdditionalInfos slice.
 func (instance AdditionalInfos) ToJSON() (flattened map[string]any) {
-	flattened = make(map[string]any)
+	flattened = make(map[string]any, len(instance))
 	for _, info := range instance {
 		flattened[info.GetKey()] = info.GetValue()
 	}
@@ -1,6 +1,7 @@
 package terror
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -718,3 +719,21 @@ func TestTypedInfo(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkFlatten(b *testing.B) {
+	for _, infoCount := range []int{1, 8, 64, 512} {
+		b.Run(fmt.Sprintf("infos=%d", infoCount), func(b *testing.B) {
+			infos := make(AdditionalInfos, 0, infoCount)
+			for i := range infoCount {
+				// Every other key is a duplicate, so flattening has work to do
+				infos = append(infos, WithIntInfo(fmt.Sprintf("key%d", i/2), i))
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				_ = infos.Flatten()
+			}
+		})
+	}
+}
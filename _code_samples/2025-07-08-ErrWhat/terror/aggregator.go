@@ -0,0 +1,272 @@
+package terror
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorGroup summarises the occurrences of errors sharing a fingerprint
+type ErrorGroup struct {
+	Fingerprint string
+	Cause       string
+	// Severity is the most severe of the occurrences, for routing the group
+	Severity Severity
+	// Count is every occurrence since the group was first seen
+	Count uint64
+	// NewCount is the occurrences since the group was last emitted
+	NewCount  uint64
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// AdditionalInfo is the flattened additional info of the latest occurrence
+	AdditionalInfo AdditionalInfos
+	// Callstack is the callstack of the first occurrence
+	Callstack string
+}
+
+type aggregatorGroup struct {
+	group       ErrorGroup
+	lastEmitted time.Time
+	element     *list.Element
+}
+
+type Aggregator struct {
+	lock               sync.Mutex
+	window             time.Duration
+	emit               func(group ErrorGroup)
+	now                func() time.Time
+	fingerprintOptions []FingerprintOption
+	idleWindows        int
+	maxGroups          int
+	groups             map[string]*aggregatorGroup
+	// recency holds the groups most recently seen first, so the least recently seen are found
+	// without scanning every group
+	recency *list.List
+}
+
+type AggregatorOption func(instance *Aggregator)
+
+// WithAggregatorClock replaces time.Now, for tests
+func WithAggregatorClock(now func() time.Time) (option AggregatorOption) {
+	return func(instance *Aggregator) {
+		instance.now = now
+	}
+}
+
+// WithAggregatorFingerprint sets the options errors are fingerprinted with
+func WithAggregatorFingerprint(options ...FingerprintOption) (option AggregatorOption) {
+	return func(instance *Aggregator) {
+		instance.fingerprintOptions = options
+	}
+}
+
+// WithAggregatorIdleWindows sets how many windows a group may go unseen before it's forgotten, the
+// default is 10
+func WithAggregatorIdleWindows(idleWindows int) (option AggregatorOption) {
+	return func(instance *Aggregator) {
+		instance.idleWindows = max(idleWindows, 1)
+	}
+}
+
+// WithAggregatorMaxGroups caps the number of groups held, the least recently seen is forgotten to
+// make room for a new one, the default is 10000
+func WithAggregatorMaxGroups(maxGroups int) (option AggregatorOption) {
+	return func(instance *Aggregator) {
+		instance.maxGroups = max(maxGroups, 1)
+	}
+}
+
+// NewAggregator groups errors by Fingerprint, calling emit with a group at most once per window, so
+// an incident logs a line per failure per window rather than a line per occurrence. Occurrences in
+// between are counted, and emitted with the group's next Record after the window, or by Flush.
+//
+// A group which stops recurring only has its trailing occurrences emitted by Flush, so run Run, or
+// call Flush on a ticker of your own:
+//
+//	aggregator := terror.NewAggregator(time.Minute, emit)
+//	go aggregator.Run(ctx)
+//
+// Groups are forgotten once idle for WithAggregatorIdleWindows windows, or when there are more than
+// WithAggregatorMaxGroups, so messages which aren't normalized can't grow memory without bound. A
+// forgotten group's trailing occurrences are emitted first. A window under a millisecond is a
+// millisecond.
+func NewAggregator(window time.Duration, emit func(group ErrorGroup), options ...AggregatorOption) (instance *Aggregator) {
+	instance = &Aggregator{
+		window:             max(window, time.Millisecond),
+		emit:               emit,
+		now:                time.Now,
+		fingerprintOptions: []FingerprintOption{},
+		idleWindows:        10,
+		maxGroups:          10000,
+		groups:             make(map[string]*aggregatorGroup),
+		recency:            list.New(),
+	}
+	for _, option := range options {
+		option(instance)
+	}
+
+	return instance
+}
+
+// Record counts the error, expected errors are skipped as they're not failures, see IsExpected
+func (instance *Aggregator) Record(err error) {
+	if IsExpected(err) {
+		return
+	}
+
+	fingerprint := Fingerprint(err, instance.fingerprintOptions...)
+	cause, callstack, additionalInfo := GetLoggingInfo(err)
+
+	instance.lock.Lock()
+	now := instance.now()
+	emitted := instance.evictIdle(now)
+	group, found := instance.groups[fingerprint]
+	if found {
+		instance.recency.MoveToFront(group.element)
+	} else {
+		if len(instance.groups) >= instance.maxGroups {
+			emitted = append(emitted, instance.evictLeastRecent(now)...)
+		}
+
+		group = &aggregatorGroup{
+			group: ErrorGroup{
+				Fingerprint: fingerprint,
+				Cause:       cause,
+				FirstSeen:   now,
+				Callstack:   callstack,
+			},
+		}
+		group.element = instance.recency.PushFront(group)
+		instance.groups[fingerprint] = group
+	}
+
+	group.group.Count++
+	group.group.NewCount++
+	group.group.LastSeen = now
+	group.group.AdditionalInfo = additionalInfo
+	severity := GetSeverity(err)
+	if !group.group.Severity.AtLeast(severity) {
+		group.group.Severity = severity
+	}
+
+	if !found || now.Sub(group.lastEmitted) >= instance.window {
+		emitted = append(emitted, instance.markEmitted(group, now))
+	}
+	instance.lock.Unlock()
+
+	// Emit outside of the lock, so a slow sink doesn't block recording
+	for _, errorGroup := range emitted {
+		instance.emit(errorGroup)
+	}
+}
+
+// Flush emits every group with occurrences since it was last emitted, regardless of the window,
+// e.g. on a ticker or at shutdown
+func (instance *Aggregator) Flush() {
+	instance.lock.Lock()
+	now := instance.now()
+	emitted := instance.evictIdle(now)
+	for _, group := range instance.groups {
+		if group.group.NewCount > 0 {
+			emitted = append(emitted, instance.markEmitted(group, now))
+		}
+	}
+	instance.lock.Unlock()
+
+	sortGroups(emitted)
+	for _, errorGroup := range emitted {
+		instance.emit(errorGroup)
+	}
+}
+
+// Run calls Flush every window until ctx is done, then flushes a final time
+func (instance *Aggregator) Run(ctx context.Context) {
+	ticker := time.NewTicker(instance.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			instance.Flush()
+			return
+		case <-ticker.C:
+			instance.Flush()
+		}
+	}
+}
+
+// Groups returns a snapshot of every group, most recently seen first
+func (instance *Aggregator) Groups() (groups []ErrorGroup) {
+	instance.lock.Lock()
+	groups = make([]ErrorGroup, 0, len(instance.groups))
+	for _, group := range instance.groups {
+		groups = append(groups, group.group)
+	}
+	instance.lock.Unlock()
+
+	sortGroups(groups)
+
+	return groups
+}
+
+// markEmitted returns a copy of the group to emit and resets its new count, the lock must be held
+func (instance *Aggregator) markEmitted(group *aggregatorGroup, now time.Time) (emitted ErrorGroup) {
+	emitted = group.group
+	group.group.NewCount = 0
+	group.lastEmitted = now
+
+	return emitted
+}
+
+// evictIdle forgets the groups unseen for idleWindows windows, returning those with occurrences still
+// to emit. Only the idle groups are visited, from the least recently seen, the lock must be held.
+func (instance *Aggregator) evictIdle(now time.Time) (emitted []ErrorGroup) {
+	emitted = make([]ErrorGroup, 0)
+	idleAfter := time.Duration(instance.idleWindows) * instance.window
+	for instance.recency.Len() > 0 {
+		leastRecent := instance.recency.Back().Value.(*aggregatorGroup)
+		if now.Sub(leastRecent.group.LastSeen) < idleAfter {
+			break
+		}
+
+		emitted = append(emitted, instance.evict(leastRecent, now)...)
+	}
+
+	return emitted
+}
+
+// evictLeastRecent forgets the least recently seen group, returning it if it has occurrences still
+// to emit, the lock must be held
+func (instance *Aggregator) evictLeastRecent(now time.Time) (emitted []ErrorGroup) {
+	if instance.recency.Len() == 0 {
+		return make([]ErrorGroup, 0)
+	}
+
+	return instance.evict(instance.recency.Back().Value.(*aggregatorGroup), now)
+}
+
+// evict forgets the group, returning it if it has occurrences still to emit, the lock must be held
+func (instance *Aggregator) evict(group *aggregatorGroup, now time.Time) (emitted []ErrorGroup) {
+	emitted = make([]ErrorGroup, 0, 1)
+	if group.group.NewCount > 0 {
+		emitted = append(emitted, instance.markEmitted(group, now))
+	}
+	instance.recency.Remove(group.element)
+	delete(instance.groups, group.group.Fingerprint)
+
+	return emitted
+}
+
+func sortGroups(groups []ErrorGroup) {
+	slices.SortFunc(groups, func(a, b ErrorGroup) int {
+		compare := b.LastSeen.Compare(a.LastSeen)
+		if compare != 0 {
+			return compare
+		}
+
+		return strings.Compare(a.Fingerprint, b.Fingerprint)
+	})
+}
@@ -0,0 +1,419 @@
+package terror
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (instance *testClock) Now() (now time.Time) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	return instance.now
+}
+
+func (instance *testClock) Advance(duration time.Duration) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	instance.now = instance.now.Add(duration)
+}
+
+type testRecord struct {
+	after time.Duration
+	err   error
+}
+
+type testEmitted struct {
+	cause    string
+	count    uint64
+	newCount uint64
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		records []testRecord
+		flush   bool
+	}
+	type result struct {
+		emitted []testEmitted
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "emits the first occurrence of a group",
+			args: &args{
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found", WithStringInfo("request_id", "a"))},
+				},
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "user 1 not found", count: 1, newCount: 1},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "suppresses occurrences within the window, emitting them with the next after it",
+			args: &args{
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found")},
+					{after: time.Second, err: newFingerprintError("user 2 not found")},
+					{after: time.Second, err: newFingerprintError("user 3 not found")},
+					{after: time.Minute, err: newFingerprintError("user 4 not found")},
+				},
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "user 1 not found", count: 1, newCount: 1},
+					{cause: "user 1 not found", count: 4, newCount: 3},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "emits each group separately",
+			args: &args{
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found")},
+					{after: time.Second, err: newFingerprintError("database unavailable")},
+					{after: time.Second, err: newFingerprintError("user 2 not found")},
+				},
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "user 1 not found", count: 1, newCount: 1},
+					{cause: "database unavailable", count: 1, newCount: 1},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "skips expected errors",
+			args: &args{
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found", ExpectedKey.Info(true))},
+					{after: time.Second, err: newFingerprintError("database unavailable")},
+				},
+				flush: true,
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "database unavailable", count: 1, newCount: 1},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "flushes suppressed occurrences",
+			args: &args{
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found")},
+					{after: time.Second, err: newFingerprintError("user 2 not found")},
+					{after: time.Second, err: newFingerprintError("database unavailable")},
+				},
+				flush: true,
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "user 1 not found", count: 1, newCount: 1},
+					{cause: "database unavailable", count: 1, newCount: 1},
+					{cause: "user 1 not found", count: 2, newCount: 1},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			clock := &testClock{now: time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)}
+			emitted := make([]testEmitted, 0)
+			instance := NewAggregator(time.Minute, func(group ErrorGroup) {
+				emitted = append(emitted, testEmitted{cause: group.Cause, count: group.Count, newCount: group.NewCount})
+			}, WithAggregatorClock(clock.Now))
+
+			// Act
+			actFunc(t)
+			for _, record := range args.records {
+				clock.Advance(record.after)
+				instance.Record(record.err)
+			}
+			if args.flush {
+				instance.Flush()
+			}
+
+			// Assert
+			assert.Equal(t, result.emitted, emitted)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestAggregatorGroups(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)}
+	instance := NewAggregator(time.Minute, func(group ErrorGroup) {}, WithAggregatorClock(clock.Now))
+
+	first := newFingerprintError("user 1 not found", WithStringInfo("request_id", "a"))
+	instance.Record(first)
+	clock.Advance(time.Second)
+	instance.Record(newFingerprintError("database unavailable"))
+	clock.Advance(time.Second)
+	instance.Record(newFingerprintError("user 2 not found", WithStringInfo("request_id", "b")))
+	clock.Advance(time.Second)
+	instance.Record(errors.New("standard error"))
+
+	groups := instance.Groups()
+
+	assert.Len(t, groups, 3)
+	assert.Equal(t, "standard error", groups[0].Cause)
+	assert.Equal(t, "unwrapped error - no callstack", groups[0].Callstack)
+
+	var users ErrorGroup
+	for _, group := range groups {
+		if group.Fingerprint == Fingerprint(first) {
+			users = group
+		}
+	}
+	assert.Equal(t, uint64(2), users.Count)
+	assert.Equal(t, clock.Now().Add(-3*time.Second), users.FirstSeen)
+	assert.Equal(t, clock.Now().Add(-time.Second), users.LastSeen)
+	assert.Equal(t, AdditionalInfos{WithStringInfo("request_id", "b")}, users.AdditionalInfo)
+	assert.Contains(t, users.Callstack, "aggregator_test.go")
+}
+
+func TestAggregatorSeverity(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)}
+	emitted := make([]ErrorGroup, 0)
+	instance := NewAggregator(time.Minute, func(group ErrorGroup) {
+		emitted = append(emitted, group)
+	}, WithAggregatorClock(clock.Now))
+
+	// Act
+	instance.Record(newFingerprintError("user 1 not found", SeverityKey.Info(SeverityWarning)))
+	instance.Record(newFingerprintError("user 2 not found", SeverityKey.Info(SeverityCritical)))
+	instance.Record(newFingerprintError("user 3 not found", SeverityKey.Info(SeverityInfo)))
+	instance.Flush()
+
+	// Assert
+	assert.Len(t, emitted, 2)
+	assert.Equal(t, SeverityWarning, emitted[0].Severity)
+	// The group keeps the most severe occurrence
+	assert.Equal(t, SeverityCritical, emitted[1].Severity)
+}
+
+func TestAggregatorEviction(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		options []AggregatorOption
+		records []testRecord
+	}
+	type result struct {
+		emitted []testEmitted
+		groups  []string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "forgets idle groups, emitting their trailing occurrences",
+			args: &args{
+				options: []AggregatorOption{WithAggregatorIdleWindows(2)},
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found")},
+					{after: time.Second, err: newFingerprintError("user 2 not found")},
+					{after: 2 * time.Minute, err: newFingerprintError("database unavailable")},
+				},
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "user 1 not found", count: 1, newCount: 1},
+					{cause: "user 1 not found", count: 2, newCount: 1},
+					{cause: "database unavailable", count: 1, newCount: 1},
+				},
+				groups: []string{"database unavailable"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "forgets the least recently seen group beyond the cap",
+			args: &args{
+				options: []AggregatorOption{WithAggregatorMaxGroups(2)},
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found")},
+					{after: time.Second, err: newFingerprintError("user 2 not found")},
+					{after: time.Second, err: newFingerprintError("database unavailable")},
+					{after: time.Second, err: errors.New("standard error")},
+				},
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "user 1 not found", count: 1, newCount: 1},
+					{cause: "database unavailable", count: 1, newCount: 1},
+					{cause: "user 1 not found", count: 2, newCount: 1},
+					{cause: "standard error", count: 1, newCount: 1},
+				},
+				groups: []string{"standard error", "database unavailable"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "keeps a group seen again beyond the cap",
+			args: &args{
+				options: []AggregatorOption{WithAggregatorMaxGroups(2)},
+				records: []testRecord{
+					{after: 0, err: newFingerprintError("user 1 not found")},
+					{after: time.Second, err: newFingerprintError("database unavailable")},
+					{after: time.Second, err: newFingerprintError("user 2 not found")},
+					{after: time.Second, err: errors.New("standard error")},
+				},
+			},
+			result: &result{
+				emitted: []testEmitted{
+					{cause: "user 1 not found", count: 1, newCount: 1},
+					{cause: "database unavailable", count: 1, newCount: 1},
+					{cause: "standard error", count: 1, newCount: 1},
+				},
+				groups: []string{"standard error", "user 1 not found"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			clock := &testClock{now: time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)}
+			emitted := make([]testEmitted, 0)
+			instance := NewAggregator(time.Minute, func(group ErrorGroup) {
+				emitted = append(emitted, testEmitted{cause: group.Cause, count: group.Count, newCount: group.NewCount})
+			}, append(args.options, WithAggregatorClock(clock.Now))...)
+
+			// Act
+			actFunc(t)
+			for _, record := range args.records {
+				clock.Advance(record.after)
+				instance.Record(record.err)
+			}
+
+			// Assert
+			assert.Equal(t, result.emitted, emitted)
+			groups := make([]string, 0)
+			for _, group := range instance.Groups() {
+				groups = append(groups, group.Cause)
+			}
+			assert.Equal(t, result.groups, groups)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestAggregatorRun(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	emitted := make(chan ErrorGroup, 4)
+	instance := NewAggregator(10*time.Millisecond, func(group ErrorGroup) {
+		emitted <- group
+	})
+	instance.Record(newFingerprintError("user 1 not found"))
+	instance.Record(newFingerprintError("user 2 not found"))
+	<-emitted
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		instance.Run(ctx)
+		close(done)
+	}()
+	trailing := <-emitted
+	cancel()
+	<-done
+
+	// Assert
+	assert.Equal(t, uint64(2), trailing.Count)
+	assert.Equal(t, uint64(1), trailing.NewCount)
+}
+
+func TestAggregatorRunWithoutWindow(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	instance := NewAggregator(0, func(group ErrorGroup) {})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	run := func() {
+		instance.Run(ctx)
+	}
+
+	// Assert
+	assert.NotPanics(t, run)
+}
@@ -0,0 +1,162 @@
+package terror
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecentError is an error held by RecentErrors, as served by its handler
+type RecentError struct {
+	Time           time.Time      `json:"time"`
+	ID             string         `json:"id,omitempty"`
+	Fingerprint    string         `json:"fingerprint"`
+	Count          uint64         `json:"count"`
+	Kind           Kind           `json:"kind,omitempty"`
+	Cause          string         `json:"cause"`
+	AdditionalInfo map[string]any `json:"additionalInfo"`
+	Printed        string         `json:"printed"`
+}
+
+// RecentErrors holds the most recent errors in a ring buffer, and is an http.Handler listing them
+// for on-call debugging. Nothing is registered by default, mount it on an internal admin mux only,
+// as the errors' additional info may be sensitive;
+//
+//	recentErrors := terror.NewRecentErrors(100)
+//	adminMux.Handle("/debug/errors", recentErrors)
+//
+// The handler serves HTML, or JSON with ?format=json or an Accept: application/json header, and
+// filters with ?kind=not_found, ?key=tenant_id, and ?key=tenant_id&value=tenant.
+type RecentErrors struct {
+	lock    sync.Mutex
+	entries []RecentError
+	next    int
+	// counts only holds the fingerprints of held entries, so it's bounded by the capacity
+	counts map[string]uint64
+	held   map[string]int
+	now    func() time.Time
+}
+
+func NewRecentErrors(capacity int) (instance *RecentErrors) {
+	return &RecentErrors{
+		entries: make([]RecentError, 0, max(capacity, 1)),
+		next:    0,
+		counts:  make(map[string]uint64),
+		held:    make(map[string]int),
+		now:     time.Now,
+	}
+}
+
+// Record holds the error, expected errors are skipped as they're not failures, see IsExpected
+func (instance *RecentErrors) Record(err error) {
+	if IsExpected(err) {
+		return
+	}
+
+	fingerprint := Fingerprint(err)
+	cause, _, additionalInfo := GetLoggingInfo(err)
+	id, _ := GetErrorID(err)
+	entry := RecentError{
+		ID:             id,
+		Fingerprint:    fingerprint,
+		Kind:           GetKind(err),
+		Cause:          cause,
+		AdditionalInfo: additionalInfo.ToJSON(),
+		Printed:        PrintError(err),
+	}
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	entry.Time = instance.now()
+	instance.counts[fingerprint]++
+	instance.held[fingerprint]++
+
+	if len(instance.entries) < cap(instance.entries) {
+		instance.entries = append(instance.entries, entry)
+		return
+	}
+
+	// Forget the count of a fingerprint once none of its errors are held
+	overwritten := instance.entries[instance.next].Fingerprint
+	instance.held[overwritten]--
+	if instance.held[overwritten] == 0 {
+		delete(instance.held, overwritten)
+		delete(instance.counts, overwritten)
+	}
+
+	instance.entries[instance.next] = entry
+	instance.next = (instance.next + 1) % len(instance.entries)
+}
+
+// Recent returns the held errors matching the filter, newest first, with Count set to the number of
+// errors recorded with the same fingerprint, including those no longer held. The count restarts once
+// no error with the fingerprint is held.
+func (instance *RecentErrors) Recent(filter func(entry RecentError) bool) (entries []RecentError) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	entries = make([]RecentError, 0, len(instance.entries))
+	for i := range instance.entries {
+		// Walk backwards from the newest entry
+		index := (instance.next - 1 - i + 2*len(instance.entries)) % len(instance.entries)
+		entry := instance.entries[index]
+		if filter != nil && !filter(entry) {
+			continue
+		}
+
+		entry.Count = instance.counts[entry.Fingerprint]
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+var recentErrorsTemplate = template.Must(template.New("recentErrors").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Recent errors</title></head>
+<body>
+<h1>Recent errors</h1>
+<p>{{len .}} errors, newest first</p>
+{{range .}}<h2>{{.Cause}}</h2>
+<p>{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}{{if .ID}} &middot; id {{.ID}}{{end}}{{if .Kind}} &middot; kind {{.Kind}}{{end}} &middot; fingerprint {{.Fingerprint}} &middot; seen {{.Count}} times</p>
+<pre>{{.Printed}}</pre>
+{{end}}</body>
+</html>
+`))
+
+func (instance *RecentErrors) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	kind := query.Get("kind")
+	key := query.Get("key")
+	value := query.Get("value")
+	hasValue := query.Has("value")
+
+	entries := instance.Recent(func(entry RecentError) bool {
+		if kind != "" && string(entry.Kind) != kind {
+			return false
+		}
+		if key != "" {
+			entryValue, found := entry.AdditionalInfo[key]
+			if !found || (hasValue && fmt.Sprintf("%v", entryValue) != value) {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	writer.Header().Set("Cache-Control", "no-store")
+	if query.Get("format") == "json" || strings.Contains(request.Header.Get("Accept"), "application/json") {
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(map[string]any{"errors": entries})
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = recentErrorsTemplate.Execute(writer, entries)
+}
@@ -0,0 +1,232 @@
+package terror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecentErrors(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		errs   []error
+		target string
+		accept string
+	}
+	type result struct {
+		causes []string
+		counts []uint64
+	}
+	type testConfig struct {
+		name          string
+		capacity      int
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "lists errors newest first",
+			capacity: 10,
+			args: &args{
+				errs: []error{
+					newFingerprintError("first error"),
+					newFingerprintError("second error"),
+				},
+				target: "/debug/errors?format=json",
+			},
+			result: &result{
+				causes: []string{"second error", "first error"},
+				counts: []uint64{1, 1},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "keeps only the most recent errors, but counts every occurrence",
+			capacity: 2,
+			args: &args{
+				errs: []error{
+					newFingerprintError("user 1 not found"),
+					newFingerprintError("user 2 not found"),
+					newFingerprintError("database unavailable"),
+					newFingerprintError("user 3 not found"),
+				},
+				target: "/debug/errors",
+				accept: "application/json",
+			},
+			result: &result{
+				causes: []string{"user 3 not found", "database unavailable"},
+				counts: []uint64{3, 1},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "restarts the count of a fingerprint once none of its errors are held",
+			capacity: 2,
+			args: &args{
+				errs: []error{
+					newFingerprintError("user 1 not found"),
+					newFingerprintError("database unavailable"),
+					newFingerprintError("database unavailable"),
+					newFingerprintError("user 2 not found"),
+				},
+				target: "/debug/errors?format=json",
+			},
+			result: &result{
+				causes: []string{"user 2 not found", "database unavailable"},
+				counts: []uint64{1, 2},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "skips expected errors",
+			capacity: 10,
+			args: &args{
+				errs: []error{
+					newFingerprintError("database unavailable"),
+					newFingerprintError("user 1 not found", ExpectedKey.Info(true)),
+				},
+				target: "/debug/errors?format=json",
+			},
+			result: &result{
+				causes: []string{"database unavailable"},
+				counts: []uint64{1},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "filters by kind",
+			capacity: 10,
+			args: &args{
+				errs: []error{
+					newFingerprintError("user not found", KindKey.Info("not_found")),
+					newFingerprintError("database unavailable", KindKey.Info("unavailable")),
+					errors.New("standard error"),
+				},
+				target: "/debug/errors?format=json&kind=not_found",
+			},
+			result: &result{
+				causes: []string{"user not found"},
+				counts: []uint64{1},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "filters by key",
+			capacity: 10,
+			args: &args{
+				errs: []error{
+					newFingerprintError("first error", WithStringInfo("tenant_id", "a")),
+					newFingerprintError("second error"),
+					newFingerprintError("third error", WithStringInfo("tenant_id", "b")),
+				},
+				target: "/debug/errors?format=json&key=tenant_id",
+			},
+			result: &result{
+				causes: []string{"third error", "first error"},
+				counts: []uint64{1, 1},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "filters by key and value",
+			capacity: 10,
+			args: &args{
+				errs: []error{
+					newFingerprintError("first error", WithIntInfo("shard", 1)),
+					newFingerprintError("second error", WithIntInfo("shard", 2)),
+				},
+				target: "/debug/errors?format=json&key=shard&value=2",
+			},
+			result: &result{
+				causes: []string{"second error"},
+				counts: []uint64{1},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			instance := NewRecentErrors(config.capacity)
+			for _, err := range args.errs {
+				instance.Record(err)
+			}
+			request := httptest.NewRequest(http.MethodGet, args.target, nil)
+			if args.accept != "" {
+				request.Header.Set("Accept", args.accept)
+			}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			actFunc(t)
+			instance.ServeHTTP(recorder, request)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+			var body struct {
+				Errors []RecentError `json:"errors"`
+			}
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+			causes := make([]string, len(body.Errors))
+			counts := make([]uint64, len(body.Errors))
+			for i, entry := range body.Errors {
+				causes[i] = entry.Cause
+				counts[i] = entry.Count
+				assert.Contains(t, entry.Printed, "Cause: "+entry.Cause)
+			}
+			assert.Equal(t, result.causes, causes)
+			assert.Equal(t, result.counts, counts)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestRecentErrorsHTML(t *testing.T) {
+	t.Parallel()
+
+	instance := NewRecentErrors(10)
+	instance.Record(newFingerprintError("<script>alert(1)</script>", WithStringInfo("key1", "value1")))
+
+	recorder := httptest.NewRecorder()
+	instance.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "&lt;script&gt;alert(1)&lt;/script&gt;")
+	assert.Contains(t, recorder.Body.String(), "key1: value1")
+	assert.NotContains(t, recorder.Body.String(), "<script>")
+}
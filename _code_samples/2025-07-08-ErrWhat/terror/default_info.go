@@ -0,0 +1,104 @@
+package terror
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultInfoRegistry holds the defaults as an immutable snapshot, replaced whole by the writers, so
+// errors are logged with them without locking or copying
+type defaultInfoRegistry struct {
+	once           sync.Once
+	lock           sync.Mutex
+	additionalInfo atomic.Pointer[AdditionalInfos]
+}
+
+var defaultInfo = &defaultInfoRegistry{}
+
+// load fills the registry from the build info on first use, so nothing is read unless it's needed
+func (instance *defaultInfoRegistry) load() {
+	instance.once.Do(func() {
+		buildInfo := BuildInfo()
+		instance.additionalInfo.Store(&buildInfo)
+	})
+}
+
+// snapshot returns the current defaults, which are shared, so must not be modified
+func (instance *defaultInfoRegistry) snapshot() (additionalInfo AdditionalInfos) {
+	instance.load()
+
+	return *instance.additionalInfo.Load()
+}
+
+// SetDefaultInfo adds infos every StructuredError is logged with, replacing any with the same key.
+// They have the lowest precedence, below the infos of every context and layer. The defaults start
+// as BuildInfo.
+func SetDefaultInfo(additionalInfo ...AdditionalInfo) {
+	defaultInfo.lock.Lock()
+	defer defaultInfo.lock.Unlock()
+
+	merged := slices.Concat(defaultInfo.snapshot(), additionalInfo).Flatten()
+	defaultInfo.additionalInfo.Store(&merged)
+}
+
+// ClearDefaultInfo removes every default info, including those from the build info
+func ClearDefaultInfo() {
+	defaultInfo.lock.Lock()
+	defer defaultInfo.lock.Unlock()
+
+	defaultInfo.load()
+	defaultInfo.additionalInfo.Store(&AdditionalInfos{})
+}
+
+// DefaultInfo returns the infos every StructuredError is logged with. They're not part of the error,
+// so Lookup won't find them.
+func DefaultInfo() (additionalInfo AdditionalInfos) {
+	return slices.Clone(defaultInfo.snapshot())
+}
+
+// BuildInfo returns the service name, i.e. the name of the main package, the module version and VCS
+// revision from the build info where available, and the hostname
+func BuildInfo() (additionalInfo AdditionalInfos) {
+	additionalInfo = AdditionalInfos{}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if ok {
+		if buildInfo.Path != "" {
+			additionalInfo = append(additionalInfo, WithStringInfo("service", getServiceName(buildInfo.Path)))
+		}
+		// Builds outside of a tagged module, e.g. go run, have the version (devel)
+		if buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+			additionalInfo = append(additionalInfo, WithStringInfo("version", buildInfo.Main.Version))
+		}
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				additionalInfo = append(additionalInfo, WithStringInfo("revision", setting.Value))
+			}
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err == nil {
+		additionalInfo = append(additionalInfo, WithStringInfo("hostname", hostname))
+	}
+
+	return additionalInfo
+}
+
+var majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// getServiceName is the last element of the main package's path, skipping a major version suffix,
+// e.g. github.com/org/service/v2 is service
+func getServiceName(packagePath string) (name string) {
+	name = path.Base(packagePath)
+	if majorVersionPattern.MatchString(name) {
+		return path.Base(path.Dir(packagePath))
+	}
+
+	return name
+}
@@ -0,0 +1,209 @@
+package terror
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDefaultInfo isn't parallel, as the defaults are global, so the parallel tests wait for it
+func TestDefaultInfo(t *testing.T) {
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		defaults AdditionalInfos
+	}
+	type result struct {
+		additionalInfo AdditionalInfos
+		flattened      AdditionalInfos
+		propagated     AdditionalInfos
+	}
+	type testConfig struct {
+		name          string
+		instance      *StructuredError
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with defaults at the lowest precedence",
+			instance: New(&testContext{
+				additionalInfo: AdditionalInfos{WithStringInfo("service", "context")},
+			}, fmt.Errorf("root error"), WithStringInfo("key1", "value1")),
+			args: &args{
+				defaults: AdditionalInfos{
+					WithStringInfo("service", "default"),
+					WithStringInfo("version", "v1.0.0"),
+				},
+			},
+			result: &result{
+				additionalInfo: AdditionalInfos{
+					WithStringInfo("service", "default"),
+					WithStringInfo("version", "v1.0.0"),
+					WithStringInfo("service", "context"),
+					WithStringInfo("key1", "value1"),
+				},
+				flattened: AdditionalInfos{
+					WithStringInfo("service", "context"),
+					WithStringInfo("version", "v1.0.0"),
+					WithStringInfo("key1", "value1"),
+				},
+				propagated: AdditionalInfos{
+					WithStringInfo("service", "context"),
+					WithStringInfo("key1", "value1"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "with defaults replaced by key",
+			instance: New(nil, fmt.Errorf("root error")),
+			args: &args{
+				defaults: AdditionalInfos{
+					WithStringInfo("version", "v1.0.0"),
+					WithStringInfo("region", "eu-west-1"),
+					WithStringInfo("version", "v2.0.0"),
+				},
+			},
+			result: &result{
+				additionalInfo: AdditionalInfos{
+					WithStringInfo("version", "v2.0.0"),
+					WithStringInfo("region", "eu-west-1"),
+				},
+				flattened: AdditionalInfos{
+					WithStringInfo("version", "v2.0.0"),
+					WithStringInfo("region", "eu-west-1"),
+				},
+				propagated: AdditionalInfos{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			t.Cleanup(ClearDefaultInfo)
+
+			// Act
+			actFunc(t)
+			for _, info := range args.defaults {
+				SetDefaultInfo(info)
+			}
+
+			// Assert
+			assert.Equal(t, result.additionalInfo, instance.getAdditionalInfo(nil))
+			assert.Equal(t, result.flattened, instance.flattenAdditionalInfo())
+			assert.Equal(t, result.propagated, GetPropagatedInfo(instance))
+
+			// Errors are logged from a shared snapshot of the defaults, which they leave unchanged
+			assert.Equal(t, result.additionalInfo[:len(DefaultInfo())], DefaultInfo())
+
+			// Defaults aren't part of the error
+			_, found := Lookup[string](instance, "version")
+			assert.False(t, found)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	additionalInfo := BuildInfo().ToJSON()
+
+	// Assert
+	// go test builds a binary without a version or VCS revision
+	_, found := additionalInfo["service"]
+	assert.True(t, found)
+	_, found = additionalInfo["version"]
+	assert.False(t, found)
+
+	hostname, err := os.Hostname()
+	if err == nil {
+		assert.Equal(t, hostname, additionalInfo["hostname"])
+	}
+}
+
+func TestGetServiceName(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		packagePath string
+	}
+	type result struct {
+		name string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "with command package",
+			args:   &args{packagePath: "github.com/org/service/cmd/api"},
+			result: &result{name: "api"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with major version suffix",
+			args:   &args{packagePath: "github.com/org/service/v2"},
+			result: &result{name: "service"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with single element",
+			args:   &args{packagePath: "service"},
+			result: &result{name: "service"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			name := getServiceName(args.packagePath)
+
+			// Assert
+			assert.Equal(t, result.name, name)
+
+			assertFunc(t)
+		})
+	}
+}
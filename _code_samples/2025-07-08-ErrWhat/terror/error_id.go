@@ -0,0 +1,60 @@
+package terror
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// idEncoding is Crockford's base32, whose alphabet is in ASCII order so IDs sort as their bytes do
+var idEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+var idGenerator atomic.Pointer[func() string]
+
+// SetIDGenerator replaces how the IDs of new errors are generated, e.g. with a counter in tests,
+// nil restores the default NewID
+func SetIDGenerator(generator func() (id string)) {
+	if generator == nil {
+		idGenerator.Store(nil)
+		return
+	}
+
+	idGenerator.Store(&generator)
+}
+
+func generateID() (id string) {
+	generator := idGenerator.Load()
+	if generator == nil {
+		return NewID()
+	}
+
+	return (*generator)()
+}
+
+// NewID returns a 20 character ID, made of the time in milliseconds followed by 48 random bits, so
+// IDs sort by when they were created
+func NewID() (id string) {
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, uint64(time.Now().UnixMilli()))
+
+	value := make([]byte, 12)
+	copy(value[:6], timestamp[2:])
+	_, _ = rand.Read(value[6:]) // #nosec G104 docs say this never returns an error
+
+	return idEncoding.EncodeToString(value)
+}
+
+// GetErrorID returns the ID the error was given when it originated, which every layer wrapping it
+// shares, so a user reporting the ID can be matched to the logs. found is false when there's no
+// StructuredError in the error tree, or it only enriches a standard error.
+func GetErrorID(err error) (id string, found bool) {
+	var structuredError *StructuredError
+	if !errors.As(err, &structuredError) {
+		return "", false
+	}
+
+	return structuredError.id, structuredError.id != ""
+}
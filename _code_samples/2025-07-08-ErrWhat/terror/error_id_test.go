@@ -0,0 +1,167 @@
+package terror
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorID isn't parallel, as the generator is global, so the parallel tests wait for it
+func TestErrorID(t *testing.T) {
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		newErr func() (err error)
+	}
+	type result struct {
+		id    string
+		found bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with root error",
+			args: &args{
+				newErr: func() (err error) {
+					return New(nil, fmt.Errorf("root error"))
+				},
+			},
+			result: &result{id: "id-1", found: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with layers inheriting the root's ID",
+			args: &args{
+				newErr: func() (err error) {
+					return New(nil, New(nil, New(nil, fmt.Errorf("root error"))))
+				},
+			},
+			result: &result{id: "id-1", found: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with structured error inside a standard error",
+			args: &args{
+				newErr: func() (err error) {
+					return fmt.Errorf("wrapped: %w", New(nil, fmt.Errorf("root error")))
+				},
+			},
+			result: &result{id: "id-1", found: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with layer over a standard error wrapping a structured error",
+			args: &args{
+				newErr: func() (err error) {
+					return New(nil, fmt.Errorf("wrapped: %w", New(nil, fmt.Errorf("root error"))))
+				},
+			},
+			result: &result{id: "id-1", found: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with error reported by a handler",
+			args: &args{
+				newErr: func() (err error) {
+					sink := NewMemorySink()
+					handler := NewHandler(func(writer http.ResponseWriter, request *http.Request) (err error) {
+						return fmt.Errorf("load user: %w", New(nil, fmt.Errorf("root error")))
+					}, WithHandlerSink(sink.Report))
+					handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+					return sink.Errors()[0]
+				},
+			},
+			result: &result{id: "id-1", found: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with standard error",
+			args: &args{
+				newErr: func() (err error) {
+					return fmt.Errorf("root error")
+				},
+			},
+			result: &result{id: "", found: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			count := 0
+			SetIDGenerator(func() (id string) {
+				count++
+				return "id-" + strconv.Itoa(count)
+			})
+			t.Cleanup(func() {
+				SetIDGenerator(func() (id string) {
+					return testErrorID
+				})
+			})
+
+			// Act
+			actFunc(t)
+			err := args.newErr()
+			id, found := GetErrorID(err)
+
+			// Assert
+			assert.Equal(t, result.id, id)
+			assert.Equal(t, result.found, found)
+			_, isStructured := err.(*StructuredError)
+			if isStructured {
+				assert.Contains(t, PrintError(err), "\nID: "+result.id+"\n")
+			}
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestNewID(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	first := NewID()
+	second := NewID()
+	time.Sleep(2 * time.Millisecond)
+	later := NewID()
+
+	// Assert
+	assert.Len(t, first, 20)
+	assert.Regexp(t, "^[0-9A-HJKMNP-TV-Z]+$", first)
+	assert.NotEqual(t, first, second)
+	// The random bits only order IDs created in the same millisecond
+	assert.Less(t, first, later)
+	assert.Less(t, second, later)
+}
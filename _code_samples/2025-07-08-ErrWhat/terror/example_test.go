@@ -4,13 +4,13 @@ import (
 	"context"
 	"crypto/rand"
 	"errors"
+	"net/http"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -30,7 +30,8 @@ type OtelContext struct {
 
 // Creates a new root context. If this is part of a distributed operation, i.e. another application began
 // the trace, then the traceID and parentID should be set to the values from that operation, otherwise
-// they can be nil and we generate IDs where appropriate.
+// they can be nil and we generate IDs where appropriate. For an incoming request, use
+// NewRemoteRootContext instead.
 func NewRootContext(name string, traceID *trace.TraceID, parentID *trace.SpanID, additionalInfo ...AdditionalInfo) (instance *OtelContext) {
 	// If there is no traceID provided, we generate a new trace ID
 	if traceID == nil {
@@ -87,6 +88,35 @@ func NewRootContext(name string, traceID *trace.TraceID, parentID *trace.SpanID,
 	}
 }
 
+// Creates a new root context continuing the trace carried by ctx, e.g. the PropagatedContext returned by
+// NewHTTPHeaderContext or NewMetadataContext, which also carries the tracestate and baggage. The
+// additional info of ctx is kept when it's a StructuredContext.
+func NewRemoteRootContext(ctx context.Context, name string, additionalInfo ...AdditionalInfo) (instance *OtelContext) {
+	structuredContext, ok := ctx.(StructuredContext)
+	if ok {
+		additionalInfo = slices.Concat(structuredContext.GetAdditionalInfo(), additionalInfo)
+	}
+
+	remoteSpanContext := trace.SpanContextFromContext(ctx)
+
+	tracer := otel.Tracer("default")
+	spanContext, otelSpan := tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithTimestamp(time.Now().UTC()),
+	)
+
+	return &OtelContext{
+		Context: spanContext,
+
+		otelSpan:       otelSpan,
+		spanID:         otelSpan.SpanContext().SpanID(),
+		traceID:        otelSpan.SpanContext().TraceID(),
+		parentID:       remoteSpanContext.SpanID(),
+		additionalInfo: additionalInfo,
+		dispatched:     false,
+	}
+}
+
 // A child context is a span within the current root, it will inherit the traceID and parentID from the current context.
 // Really it's just a helper so you don't have to worrk about trace ID and parentID, but it also helps to document
 // where a span enters the system (root) and where it is expected to be nested (child).
@@ -135,51 +165,10 @@ func (instance *OtelContext) Close(err *error) {
 	}
 
 	// Add the additional info to the span
-	attributes := make([]attribute.KeyValue, len(instance.additionalInfo))
-	for i, info := range instance.additionalInfo {
-		switch value := info.GetValue().(type) {
-		case bool:
-			attributes[i] = attribute.Bool(info.GetKey(), value)
-		case int64:
-			attributes[i] = attribute.Int64(info.GetKey(), value)
-		case uint64:
-			// otel/attribute doesn't support uint64
-			attributes[i] = attribute.Int64(info.GetKey(), int64(value))
-		case float64:
-			attributes[i] = attribute.Float64(info.GetKey(), value)
-		case string:
-			attributes[i] = attribute.String(info.GetKey(), value)
-		case []bool:
-			attributes[i] = attribute.BoolSlice(info.GetKey(), value)
-		case []int64:
-			attributes[i] = attribute.Int64Slice(info.GetKey(), value)
-		case []uint64:
-			// otel/attribute doesn't support uint64
-			values := make([]int64, len(value))
-			for j, v := range value {
-				values[j] = int64(v)
-			}
-			attributes[i] = attribute.Int64Slice(info.GetKey(), values)
-		case []float64:
-			attributes[i] = attribute.Float64Slice(info.GetKey(), value)
-		case []string:
-			attributes[i] = attribute.StringSlice(info.GetKey(), value)
-		}
-	}
-
-	instance.otelSpan.SetAttributes(attributes...)
+	instance.otelSpan.SetAttributes(ToAttributes(instance.additionalInfo)...)
 
-	// Set the status
-	var status codes.Code
-	var cause string
-	if *err != nil {
-		status = codes.Error
-		cause = (*err).Error()
-	} else {
-		status = codes.Ok
-	}
-
-	instance.otelSpan.SetStatus(status, cause)
+	// Set the status, expected errors are OK with an event rather than failing the span
+	SetSpanStatus(instance.otelSpan, *err)
 
 	instance.otelSpan.End(
 		trace.WithTimestamp(time.Now().UTC()),
@@ -266,3 +255,25 @@ func TestExample(t *testing.T) {
 		WithStringInfo("error2", "value2"),
 	})
 }
+
+func TestNewRemoteRootContext(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	header := http.Header{}
+	header.Set("traceparent", "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01")
+	header.Set("baggage", "tenant=acme")
+	propagatedContext := NewHTTPHeaderContext(context.Background(), header, WithBaggageInfo())
+
+	// Act
+	instance := NewRemoteRootContext(propagatedContext, "handle request", WithStringInfo("key1", "value1"))
+	otherInstance := NewRemoteRootContext(propagatedContext, "handle other request", WithStringInfo("key2", "value2"))
+
+	// Assert
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", instance.traceID.String())
+	assert.Equal(t, "0102030405060708", instance.parentID.String())
+	assert.Equal(t, AdditionalInfos{WithStringInfo("tenant", "acme"), WithStringInfo("key1", "value1")}, instance.GetAdditionalInfo())
+	// Roots from the same context don't overwrite each other's info
+	assert.Equal(t, AdditionalInfos{WithStringInfo("tenant", "acme"), WithStringInfo("key2", "value2")}, otherInstance.GetAdditionalInfo())
+	assert.Equal(t, AdditionalInfos{WithStringInfo("tenant", "acme")}, propagatedContext.GetAdditionalInfo())
+}
@@ -0,0 +1,87 @@
+package terror
+
+import (
+	"errors"
+	"sync"
+)
+
+type expectedRegistry struct {
+	lock     sync.RWMutex
+	matchers []func(err error) bool
+}
+
+var expectedErrors = &expectedRegistry{}
+
+// ExpectedKey marks an error as expected, i.e. handled as part of normal operation, such as a user
+// not being found, so it's recorded as informational rather than as a failure. As with
+// SeverityOverrideKey the outermost marker wins, as the caller knows best whether a failure it
+// handled is expected, e.g. marking false where a dependency marked true.
+//
+//	err = terror.New(ctx, err, terror.ExpectedKey.Info(true))
+var ExpectedKey = NewKey[bool]("expected")
+
+// RegisterExpected registers sentinel errors which are always expected, matched with errors.Is
+func RegisterExpected(targets ...error) {
+	expectedErrors.lock.Lock()
+	defer expectedErrors.lock.Unlock()
+
+	for _, target := range targets {
+		expectedErrors.matchers = append(expectedErrors.matchers, func(err error) bool {
+			return errors.Is(err, target)
+		})
+	}
+}
+
+// RegisterExpectedType registers an error type which is always expected, matched with errors.As
+//
+//	terror.RegisterExpectedType[*ValidationError]()
+func RegisterExpectedType[T error]() {
+	expectedErrors.lock.Lock()
+	defer expectedErrors.lock.Unlock()
+
+	expectedErrors.matchers = append(expectedErrors.matchers, func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	})
+}
+
+// ClearExpected removes every registered expected error, markers on errors are unaffected
+func ClearExpected() {
+	expectedErrors.lock.Lock()
+	defer expectedErrors.lock.Unlock()
+
+	expectedErrors.matchers = nil
+}
+
+// IsExpected reports whether the outermost ExpectedKey of the error is true, or without one, whether
+// it matches a registered expected error
+func IsExpected(err error) (expected bool) {
+	if err == nil {
+		return false
+	}
+
+	// LookupAll is outermost first
+	markers := LookupAll[bool](err, ExpectedKey.Name())
+	if len(markers) > 0 {
+		return markers[0]
+	}
+
+	expectedErrors.lock.RLock()
+	defer expectedErrors.lock.RUnlock()
+
+	for _, matcher := range expectedErrors.matchers {
+		if matcher(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchUnexpected matches errors which aren't expected, for Filter, e.g. so only failures reach an
+// issue tracker
+func MatchUnexpected() (match func(err error) bool) {
+	return func(err error) bool {
+		return !IsExpected(err)
+	}
+}
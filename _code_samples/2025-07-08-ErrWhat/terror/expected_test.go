@@ -0,0 +1,151 @@
+package terror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestExpected = errors.New("user not found")
+
+type testValidationError struct {
+	field string
+}
+
+func (instance *testValidationError) Error() (errString string) {
+	return "invalid " + instance.field
+}
+
+// TestExpected isn't parallel, as the registry is global, so the parallel tests wait for it
+func TestExpected(t *testing.T) {
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+	}
+	type result struct {
+		expected bool
+		severity Severity
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "with registered sentinel",
+			args:   &args{err: New(nil, fmt.Errorf("get user: %w", errTestExpected))},
+			result: &result{expected: true, severity: SeverityInfo},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with registered type",
+			args:   &args{err: New(nil, New(nil, &testValidationError{field: "email"}))},
+			result: &result{expected: true, severity: SeverityInfo},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with marker",
+			args:   &args{err: New(nil, fmt.Errorf("root error"), ExpectedKey.Info(true), SeverityKey.Info(SeverityCritical))},
+			result: &result{expected: true, severity: SeverityInfo},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with marker unsetting a registered error",
+			args:   &args{err: New(nil, errTestExpected, ExpectedKey.Info(false))},
+			result: &result{expected: false, severity: SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with outermost marker taking precedence",
+			args:   &args{err: New(nil, New(nil, fmt.Errorf("root error"), ExpectedKey.Info(true)), ExpectedKey.Info(false))},
+			result: &result{expected: false, severity: SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with outermost marker setting a deeper unset",
+			args:   &args{err: New(nil, New(nil, errTestExpected, ExpectedKey.Info(false)), ExpectedKey.Info(true))},
+			result: &result{expected: true, severity: SeverityInfo},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with override taking precedence",
+			args:   &args{err: New(nil, errTestExpected, SeverityOverrideKey.Info(SeverityWarning))},
+			result: &result{expected: true, severity: SeverityWarning},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with unexpected error",
+			args:   &args{err: New(nil, fmt.Errorf("connection reset"))},
+			result: &result{expected: false, severity: SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with nil error",
+			args:   &args{err: nil},
+			result: &result{expected: false, severity: SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			RegisterExpected(errTestExpected)
+			RegisterExpectedType[*testValidationError]()
+			t.Cleanup(ClearExpected)
+
+			// Act
+			actFunc(t)
+			expected := IsExpected(args.err)
+
+			// Assert
+			assert.Equal(t, result.expected, expected)
+			assert.Equal(t, !result.expected, MatchUnexpected()(args.err))
+			assert.Equal(t, result.severity, GetSeverity(args.err))
+
+			assertFunc(t)
+		})
+	}
+}
+
+// TestClearExpected isn't parallel, as the registry is global
+func TestClearExpected(t *testing.T) {
+	// Arrange
+	RegisterExpected(errTestExpected)
+
+	// Act
+	ClearExpected()
+
+	// Assert
+	assert.False(t, IsExpected(errTestExpected))
+}
@@ -0,0 +1,109 @@
+package terror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// FingerprintKey overrides the computed fingerprint, for grouping errors Fingerprint can't tell are
+// the same, or splitting ones it can't tell apart
+var FingerprintKey = NewKey[string]("fingerprint")
+
+type fingerprintOptions struct {
+	frames   int
+	infoKeys []string
+}
+
+type FingerprintOption func(options *fingerprintOptions)
+
+// WithFingerprintFrames sets how many of the top in-module frames are part of the fingerprint, the
+// default is 3
+func WithFingerprintFrames(count int) (option FingerprintOption) {
+	return func(options *fingerprintOptions) {
+		options.frames = count
+	}
+}
+
+// WithFingerprintKeys sets the additional info keys whose values are part of the fingerprint, the
+// default is KindKey. Only choose keys with a few distinct values, never IDs.
+func WithFingerprintKeys(keys ...string) (option FingerprintOption) {
+	return func(options *fingerprintOptions) {
+		options.infoKeys = keys
+	}
+}
+
+var (
+	uuidPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexPattern    = regexp.MustCompile(`\b(0x[0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`)
+	numberPattern = regexp.MustCompile(`[0-9]+`)
+)
+
+// normalizeMessage replaces the parts of a message which vary between occurrences of the same
+// failure, such as IDs, addresses and counts. Hex runs of 8 or more are IDs whether or not they have
+// digits, e.g. deadbeef, as is any run prefixed with 0x.
+func normalizeMessage(message string) (normalized string) {
+	normalized = uuidPattern.ReplaceAllString(message, "<uuid>")
+	normalized = hexPattern.ReplaceAllString(normalized, "<hex>")
+	normalized = numberPattern.ReplaceAllString(normalized, "<n>")
+
+	return normalized
+}
+
+// Fingerprint returns a stable hash identifying the failure, so occurrences of the same bug can be
+// grouped however much their additional info differs. It's made from the type of the cause, the
+// normalized message, the functions of the top in-module frames, and the values of selected keys.
+// A FingerprintKey info in the chain is returned instead.
+func Fingerprint(err error, options ...FingerprintOption) (fingerprint string) {
+	override, found := FingerprintKey.Lookup(err)
+	if found {
+		return override
+	}
+
+	resolved := &fingerprintOptions{
+		frames:   3,
+		infoKeys: []string{KindKey.Name()},
+	}
+	for _, option := range options {
+		option(resolved)
+	}
+
+	cause := err
+	structuredError, isStructured := err.(*StructuredError)
+	for isStructured {
+		cause = structuredError.cause
+		structuredError, isStructured = cause.(*StructuredError)
+	}
+
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "type:%T\n", cause)
+	_, _ = fmt.Fprintf(hash, "message:%s\n", normalizeMessage(err.Error()))
+
+	structuredError, isStructured = err.(*StructuredError)
+	if isStructured {
+		moduleRoot := getModuleRoot()
+		count := 0
+		for _, frame := range getFrames(structuredError.getOriginCallstack()) {
+			if count >= resolved.frames {
+				break
+			}
+			if isLibraryFrame(frame, moduleRoot) {
+				continue
+			}
+
+			_, _ = fmt.Fprintf(hash, "frame:%s\n", frame.Function)
+			count++
+		}
+
+		additionalInfo := structuredError.flattenAdditionalInfo().ToJSON()
+		for _, key := range resolved.infoKeys {
+			value, found := additionalInfo[key]
+			if found {
+				_, _ = fmt.Fprintf(hash, "info:%s=%v\n", key, value)
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
@@ -0,0 +1,287 @@
+package terror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFingerprintError(message string, additionalInfo ...AdditionalInfo) (err error) {
+	return New(nil, errors.New(message), additionalInfo...)
+}
+
+func newOtherFingerprintError(message string, additionalInfo ...AdditionalInfo) (err error) {
+	return New(nil, errors.New(message), additionalInfo...)
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		other   error
+		options []FingerprintOption
+	}
+	type result struct {
+		same bool
+	}
+	type testConfig struct {
+		name          string
+		instance      error
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "matches when the message and additional info only differ by ids",
+			instance: newFingerprintError("user 12 not found in 3f2b8a9c-1d4e-4f6a-8b7c-9d0e1f2a3b4c", WithStringInfo("request_id", "a")),
+			args: &args{
+				other: newFingerprintError("user 345 not found in 8e1a6c2d-7b3f-4a5e-9c8d-1e2f3a4b5c6d", WithStringInfo("request_id", "b")),
+			},
+			result: &result{
+				same: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "differs when the message differs",
+			instance: newFingerprintError("user not found"),
+			args: &args{
+				other: newFingerprintError("user already exists"),
+			},
+			result: &result{
+				same: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "differs when the cause type differs",
+			instance: New(nil, errors.New("root error")),
+			args: &args{
+				other: New(nil, &BadError{}),
+			},
+			result: &result{
+				same: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "differs when created in a different function",
+			instance: newFingerprintError("root error"),
+			args: &args{
+				other: newOtherFingerprintError("root error"),
+			},
+			result: &result{
+				same: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "matches when created in a different function, if frames are excluded",
+			instance: newFingerprintError("root error"),
+			args: &args{
+				other:   newOtherFingerprintError("root error"),
+				options: []FingerprintOption{WithFingerprintFrames(0)},
+			},
+			result: &result{
+				same: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "differs when the kind differs",
+			instance: newFingerprintError("root error", KindKey.Info("not_found")),
+			args: &args{
+				other: newFingerprintError("root error", KindKey.Info("internal")),
+			},
+			result: &result{
+				same: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "differs when a selected key differs",
+			instance: newFingerprintError("root error", WithStringInfo("table", "users")),
+			args: &args{
+				other:   newFingerprintError("root error", WithStringInfo("table", "orders")),
+				options: []FingerprintOption{WithFingerprintKeys("table")},
+			},
+			result: &result{
+				same: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "matches when the fingerprint is overridden",
+			instance: newFingerprintError("user not found", FingerprintKey.Info("users")),
+			args: &args{
+				other: New(nil, newOtherFingerprintError("user already exists"), FingerprintKey.Info("users")),
+			},
+			result: &result{
+				same: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "matches when the message only differs by hex ids without digits",
+			instance: newFingerprintError("object deadbeef not found"),
+			args: &args{
+				other: newFingerprintError("object cafebabe not found"),
+			},
+			result: &result{
+				same: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "matches standard go errors with the same message",
+			instance: fmt.Errorf("root error 1"),
+			args: &args{
+				other: fmt.Errorf("root error 2"),
+			},
+			result: &result{
+				same: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			fingerprint := Fingerprint(instance, args.options...)
+			other := Fingerprint(args.other, args.options...)
+
+			// Assert
+			assert.NotEmpty(t, fingerprint)
+			assert.Equal(t, result.same, fingerprint == other)
+			assert.Equal(t, fingerprint, Fingerprint(instance, args.options...))
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		message string
+	}
+	type result struct {
+		normalized string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "leaves a static message",
+			args:   &args{message: "something bad happened"},
+			result: &result{normalized: "something bad happened"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "replaces numbers",
+			args:   &args{message: "failed on attempt 3 of 10"},
+			result: &result{normalized: "failed on attempt <n> of <n>"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "replaces uuids",
+			args:   &args{message: "user 3f2b8a9c-1d4e-4f6a-8b7c-9d0e1f2a3b4c not found"},
+			result: &result{normalized: "user <uuid> not found"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "replaces hex ids without digits",
+			args:   &args{message: "object deadbeef at 0xcafe"},
+			result: &result{normalized: "object <hex> at <hex>"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "leaves short hex words",
+			args:   &args{message: "failed to decode face a1b2"},
+			result: &result{normalized: "failed to decode face a<n>b<n>"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "replaces hex ids and addresses",
+			args:   &args{message: "object 5f3a9c1e2b at 0xc000123"},
+			result: &result{normalized: "object <hex> at <hex>"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			normalized := normalizeMessage(args.message)
+
+			// Assert
+			assert.Equal(t, result.normalized, normalized)
+
+			assertFunc(t)
+		})
+	}
+}
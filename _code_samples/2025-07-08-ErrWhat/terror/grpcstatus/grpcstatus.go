@@ -0,0 +1,300 @@
+// Package grpcstatus converts between StructuredErrors and gRPC statuses, so the kind and additional
+// info of an error survive the boundary between services
+package grpcstatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MrShiny608/terror/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Domain is the ErrorInfo domain of statuses created by ToStatus, FromError only restores the kind
+// and additional info of statuses from this domain
+const Domain = "terror"
+
+// RemoteCallstackKey is the additional info key of the server's callstack, on an error rebuilt by
+// FromError
+const RemoteCallstackKey = "remote_callstack"
+
+var kindCodes = map[terror.Kind]codes.Code{
+	terror.KindInvalidArgument:    codes.InvalidArgument,
+	terror.KindNotFound:           codes.NotFound,
+	terror.KindAlreadyExists:      codes.AlreadyExists,
+	terror.KindPermissionDenied:   codes.PermissionDenied,
+	terror.KindUnauthenticated:    codes.Unauthenticated,
+	terror.KindResourceExhausted:  codes.ResourceExhausted,
+	terror.KindFailedPrecondition: codes.FailedPrecondition,
+	terror.KindAborted:            codes.Aborted,
+	terror.KindUnavailable:        codes.Unavailable,
+	terror.KindDeadlineExceeded:   codes.DeadlineExceeded,
+	terror.KindCanceled:           codes.Canceled,
+	terror.KindUnimplemented:      codes.Unimplemented,
+	terror.KindInternal:           codes.Internal,
+}
+
+type options struct {
+	codes     map[terror.Kind]codes.Code
+	debugInfo bool
+}
+
+type Option func(options *options)
+
+// WithCode maps the kind to the code, adding to or replacing the mapping of the well-known kinds
+func WithCode(kind terror.Kind, code codes.Code) (option Option) {
+	return func(options *options) {
+		options.codes[kind] = code
+	}
+}
+
+// WithDebugInfo adds the DebugInfo detail, i.e. the callstack, which is omitted by default as it
+// exposes the service's internals, so only use it where the clients are trusted, e.g. internally
+func WithDebugInfo() (option Option) {
+	return func(options *options) {
+		options.debugInfo = true
+	}
+}
+
+func getOptions(opts []Option) (resolved *options) {
+	resolved = &options{
+		codes:     maps.Clone(kindCodes),
+		debugInfo: false,
+	}
+	for _, option := range opts {
+		option(resolved)
+	}
+
+	return resolved
+}
+
+// ToStatus converts the error to a status, with the code mapped from its kind, an ErrorInfo detail
+// whose metadata is the kind and the flattened additional info, without the defaults, as the
+// receiver has defaults of its own, and whose reason is the kind for display, and with
+// WithDebugInfo a DebugInfo detail with the callstack. Errors without a kind keep the code of any
+// status or context error they wrap, otherwise they're Unknown. Errors wrapping a status keep its
+// other details, and their message without its "rpc error: ..." prefix.
+func ToStatus(err error, opts ...Option) (st *status.Status) {
+	resolved := getOptions(opts)
+
+	kind := terror.GetKind(err)
+	code, found := resolved.codes[kind]
+	if !found {
+		code = getCode(err)
+	}
+
+	cause, callstack, _ := terror.GetLoggingInfo(err)
+	additionalInfo := terror.GetPropagatedInfo(err)
+	message := cause
+	foreignDetails := make([]*anypb.Any, 0)
+	causeStatus, found := getCauseStatus(err)
+	if found {
+		// Drop the "rpc error: ..." prefix of the status, keeping any context it was wrapped with, so
+		// the message doesn't grow with each service the error passes through
+		prefix := fmt.Sprintf("rpc error: code = %s desc = ", causeStatus.Code())
+		message = strings.Replace(cause, prefix, "", 1)
+		foreignDetails = getForeignDetails(causeStatus)
+	}
+	st = status.FromProto(&spb.Status{
+		Code:    int32(code), // #nosec G115 codes are small
+		Message: message,
+		Details: foreignDetails,
+	})
+
+	metadata := make(map[string]string, len(additionalInfo)+1)
+	for key, value := range additionalInfo.ToJSON() {
+		metadata[key] = fmt.Sprintf("%v", value)
+	}
+	// The kind is restored from the metadata as it is, as the upper-cased reason loses the case of
+	// custom kinds
+	delete(metadata, terror.KindKey.Name())
+	if kind != "" {
+		metadata[terror.KindKey.Name()] = string(kind)
+	}
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   strings.ToUpper(string(kind)),
+			Domain:   Domain,
+			Metadata: metadata,
+		},
+	}
+
+	_, isStructured := err.(*terror.StructuredError)
+	if resolved.debugInfo && isStructured {
+		details = append(details, &errdetails.DebugInfo{
+			StackEntries: strings.Split(callstack, "\n"),
+			Detail:       cause,
+		})
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// getCauseStatus finds the status the error wraps, unlike status.FromError its message isn't replaced
+// with the wrapping error's
+func getCauseStatus(err error) (st *status.Status, found bool) {
+	var grpcStatus interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcStatus) {
+		return nil, false
+	}
+
+	st = grpcStatus.GRPCStatus()
+
+	return st, st != nil
+}
+
+// getForeignDetails returns the details of the status other than those ToStatus sets, which are
+// replaced, so a DebugInfo from another service is dropped rather than forwarded
+func getForeignDetails(st *status.Status) (details []*anypb.Any) {
+	details = make([]*anypb.Any, 0)
+	for _, detail := range st.Proto().GetDetails() {
+		if detail.MessageIs(&errdetails.DebugInfo{}) {
+			continue
+		}
+		if detail.MessageIs(&errdetails.ErrorInfo{}) {
+			errorInfo := &errdetails.ErrorInfo{}
+			if detail.UnmarshalTo(errorInfo) == nil && errorInfo.GetDomain() == Domain {
+				continue
+			}
+		}
+
+		details = append(details, detail)
+	}
+
+	return details
+}
+
+// typedKeys restore the metadata of the keys terror reads itself with their types, so their Lookups
+// still find them on the client
+var typedKeys = map[string]func(value string) (info terror.AdditionalInfo, ok bool){
+	terror.KindKey.Name(): func(value string) (info terror.AdditionalInfo, ok bool) {
+		return terror.KindKey.Info(terror.Kind(value)), true
+	},
+	terror.SeverityKey.Name(): func(value string) (info terror.AdditionalInfo, ok bool) {
+		return terror.SeverityKey.Info(terror.Severity(value)), true
+	},
+	terror.SeverityOverrideKey.Name(): func(value string) (info terror.AdditionalInfo, ok bool) {
+		return terror.SeverityOverrideKey.Info(terror.Severity(value)), true
+	},
+	terror.ExpectedKey.Name(): func(value string) (info terror.AdditionalInfo, ok bool) {
+		expected, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, false
+		}
+
+		return terror.ExpectedKey.Info(expected), true
+	},
+}
+
+// restoreInfo restores the metadata with its type where it's a typed key, otherwise as a string
+func restoreInfo(key string, value string) (info terror.AdditionalInfo) {
+	restore, found := typedKeys[key]
+	if found {
+		info, ok := restore(value)
+		if ok {
+			return info
+		}
+	}
+
+	return terror.WithStringInfo(key, value)
+}
+
+func getCode(err error) (code codes.Code) {
+	code = status.Code(err)
+	if code != codes.Unknown {
+		return code
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
+
+// FromError rebuilds a StructuredError from a status error, the cause is the status error so its
+// code is still available with status.Code. The kind and additional info are restored from the
+// ErrorInfo metadata, as string values other than the keys terror reads itself, such as the kind,
+// the severity and whether it's expected, and the server's callstack is set as RemoteCallstackKey.
+// Errors which aren't statuses are returned as they are.
+func FromError(ctx terror.StructuredContext, err error, opts ...Option) (rebuilt error) {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	resolved := getOptions(opts)
+	additionalInfo := make(terror.AdditionalInfos, 0)
+	kindFound := false
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() != Domain {
+				continue
+			}
+			metadata := detail.GetMetadata()
+			_, kindFound = metadata[terror.KindKey.Name()]
+			for _, key := range slices.Sorted(maps.Keys(metadata)) {
+				additionalInfo = append(additionalInfo, restoreInfo(key, metadata[key]))
+			}
+		case *errdetails.DebugInfo:
+			additionalInfo = append(additionalInfo, terror.WithStringSliceInfo(RemoteCallstackKey, detail.GetStackEntries()))
+		}
+	}
+
+	// Statuses from other services get the kind mapped to their code
+	if !kindFound {
+		for _, kind := range slices.Sorted(maps.Keys(resolved.codes)) {
+			if resolved.codes[kind] == st.Code() {
+				additionalInfo = append(additionalInfo, terror.KindKey.Info(kind))
+				break
+			}
+		}
+	}
+
+	return terror.New(ctx, st.Err(), additionalInfo...)
+}
+
+// UnaryServerInterceptor converts the errors returned by handlers with ToStatus
+func UnaryServerInterceptor(opts ...Option) (interceptor grpc.UnaryServerInterceptor) {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response any, err error) {
+		response, err = handler(ctx, request)
+		if err != nil {
+			return response, ToStatus(err, opts...).Err()
+		}
+
+		return response, nil
+	}
+}
+
+// UnaryClientInterceptor converts the errors of calls with FromError
+func UnaryClientInterceptor(opts ...Option) (interceptor grpc.UnaryClientInterceptor) {
+	return func(ctx context.Context, method string, request any, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOptions ...grpc.CallOption) (err error) {
+		err = invoker(ctx, method, request, reply, cc, callOptions...)
+
+		return FromError(nil, err, opts...)
+	}
+}
@@ -0,0 +1,564 @@
+package grpcstatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MrShiny608/terror/v2"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestMain(m *testing.M) {
+	// The tests expect exactly the additional info they create, so start without the build info
+	terror.ClearDefaultInfo()
+
+	os.Exit(m.Run())
+}
+
+type testHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	err error
+}
+
+func (instance *testHealthServer) Check(ctx context.Context, request *grpc_health_v1.HealthCheckRequest) (response *grpc_health_v1.HealthCheckResponse, err error) {
+	return nil, instance.err
+}
+
+func getErrorInfo(st *status.Status) (errorInfo *errdetails.ErrorInfo) {
+	for _, detail := range st.Details() {
+		errorInfo, ok := detail.(*errdetails.ErrorInfo)
+		if ok {
+			return errorInfo
+		}
+	}
+
+	return nil
+}
+
+func getDebugInfo(st *status.Status) (debugInfo *errdetails.DebugInfo) {
+	for _, detail := range st.Details() {
+		debugInfo, ok := detail.(*errdetails.DebugInfo)
+		if ok {
+			return debugInfo
+		}
+	}
+
+	return nil
+}
+
+func getResourceInfo(st *status.Status) (resourceInfo *errdetails.ResourceInfo) {
+	for _, detail := range st.Details() {
+		resourceInfo, ok := detail.(*errdetails.ResourceInfo)
+		if ok {
+			return resourceInfo
+		}
+	}
+
+	return nil
+}
+
+// newStatusError returns a status error with a detail of its own, as a handler might
+func newStatusError(t *testing.T) (err error) {
+	st, err := status.New(codes.NotFound, "user missing").WithDetails(&errdetails.ResourceInfo{ResourceType: "user", ResourceName: "1"})
+	assert.NoError(t, err)
+
+	return st.Err()
+}
+
+func TestToStatus(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err     error
+		options []Option
+	}
+	type result struct {
+		code         codes.Code
+		message      string
+		reason       string
+		metadata     map[string]string
+		debugInfo    bool
+		resourceInfo bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with kind",
+			args: &args{
+				err:     terror.New(nil, fmt.Errorf("user not found"), terror.KindKey.Info(terror.KindNotFound), terror.WithIntInfo("user_id", 1)),
+				options: []Option{WithDebugInfo()},
+			},
+			result: &result{
+				code:      codes.NotFound,
+				message:   "user not found",
+				reason:    "NOT_FOUND",
+				metadata:  map[string]string{"kind": "not_found", "user_id": "1"},
+				debugInfo: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with custom kind code",
+			args: &args{
+				err:     terror.New(nil, fmt.Errorf("over quota"), terror.KindKey.Info("quota")),
+				options: []Option{WithCode("quota", codes.ResourceExhausted)},
+			},
+			result: &result{
+				code:     codes.ResourceExhausted,
+				message:  "over quota",
+				reason:   "QUOTA",
+				metadata: map[string]string{"kind": "quota"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with wrapped context error",
+			args: &args{
+				err:     terror.New(nil, fmt.Errorf("query failed: %w", context.DeadlineExceeded)),
+				options: []Option{},
+			},
+			result: &result{
+				code:      codes.DeadlineExceeded,
+				message:   "query failed: context deadline exceeded",
+				debugInfo: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with wrapped status error, keeping its context and details",
+			args: &args{
+				options: []Option{},
+			},
+			result: &result{
+				code:         codes.NotFound,
+				message:      "loading user: user missing",
+				debugInfo:    false,
+				resourceInfo: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				arrangeFunc = func(t *testing.T) {
+					args.err = terror.New(nil, fmt.Errorf("loading user: %w", newStatusError(t)))
+				}
+				assertFunc = func(t *testing.T) {
+					// Passing the error on to another service doesn't prefix the message again
+					passedOn := ToStatus(FromError(nil, ToStatus(args.err).Err()))
+					assert.Equal(t, result.message, passedOn.Message())
+				}
+
+				return arrangeFunc, func(t *testing.T) {}, assertFunc
+			},
+		},
+		{
+			name: "with standard error",
+			args: &args{
+				err:     errors.New("standard error"),
+				options: []Option{},
+			},
+			result: &result{
+				code:    codes.Unknown,
+				message: "standard error",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			st := ToStatus(args.err, args.options...)
+
+			// Assert
+			assert.Equal(t, result.code, st.Code())
+			assert.Equal(t, result.message, st.Message())
+
+			errorInfo := getErrorInfo(st)
+			assert.NotNil(t, errorInfo)
+			assert.Equal(t, Domain, errorInfo.GetDomain())
+			assert.Equal(t, result.reason, errorInfo.GetReason())
+			assert.Equal(t, result.metadata, errorInfo.GetMetadata())
+
+			debugInfo := getDebugInfo(st)
+			assert.Equal(t, result.debugInfo, debugInfo != nil)
+			if result.debugInfo {
+				_, callstack, _ := terror.GetLoggingInfo(args.err)
+				assert.Equal(t, strings.Split(callstack, "\n"), debugInfo.GetStackEntries())
+			}
+			assert.Equal(t, result.resourceInfo, getResourceInfo(st) != nil)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+	}
+	type result struct {
+		err            error
+		code           codes.Code
+		kind           terror.Kind
+		additionalInfo map[string]any
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with terror status",
+			args: &args{
+				err: ToStatus(terror.New(nil, fmt.Errorf("user not found"), terror.KindKey.Info(terror.KindNotFound), terror.WithStringInfo("user_id", "1"))).Err(),
+			},
+			result: &result{
+				code: codes.NotFound,
+				kind: terror.KindNotFound,
+				additionalInfo: map[string]any{
+					"kind":    terror.KindNotFound,
+					"user_id": "1",
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with custom kind",
+			args: &args{
+				err: ToStatus(terror.New(nil, fmt.Errorf("slow down"), terror.KindKey.Info("RateLimited")), WithCode("RateLimited", codes.ResourceExhausted)).Err(),
+			},
+			result: &result{
+				code: codes.ResourceExhausted,
+				kind: "RateLimited",
+				additionalInfo: map[string]any{
+					"kind": terror.Kind("RateLimited"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with severity",
+			args: &args{
+				err: ToStatus(terror.New(nil, fmt.Errorf("retrying"), terror.KindKey.Info(terror.KindUnavailable), terror.SeverityKey.Info(terror.SeverityWarning))).Err(),
+			},
+			result: &result{
+				code: codes.Unavailable,
+				kind: terror.KindUnavailable,
+				additionalInfo: map[string]any{
+					"kind":     terror.KindUnavailable,
+					"severity": terror.SeverityWarning,
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {
+					rebuilt := FromError(nil, args.err)
+					assert.Equal(t, terror.SeverityWarning, terror.GetSeverity(rebuilt))
+				}
+			},
+		},
+		{
+			name: "with expected error",
+			args: &args{
+				err: ToStatus(terror.New(nil, fmt.Errorf("user not found"), terror.KindKey.Info(terror.KindNotFound), terror.ExpectedKey.Info(true))).Err(),
+			},
+			result: &result{
+				code: codes.NotFound,
+				kind: terror.KindNotFound,
+				additionalInfo: map[string]any{
+					"kind":     terror.KindNotFound,
+					"expected": true,
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {
+					rebuilt := FromError(nil, args.err)
+					assert.True(t, terror.IsExpected(rebuilt))
+					assert.Equal(t, terror.SeverityInfo, terror.GetSeverity(rebuilt))
+				}
+			},
+		},
+		{
+			name: "with status from another service",
+			args: &args{
+				err: status.Error(codes.PermissionDenied, "denied"),
+			},
+			result: &result{
+				code: codes.PermissionDenied,
+				kind: terror.KindPermissionDenied,
+				additionalInfo: map[string]any{
+					"kind": terror.KindPermissionDenied,
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with standard error",
+			args: &args{
+				err: errors.New("standard error"),
+			},
+			result: &result{},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					result.err = args.err
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with nil error",
+			args: &args{
+				err: nil,
+			},
+			result: &result{},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			rebuilt := FromError(nil, args.err)
+
+			// Assert
+			if result.additionalInfo == nil {
+				assert.Equal(t, result.err, rebuilt)
+				assertFunc(t)
+				return
+			}
+
+			_, isStructured := rebuilt.(*terror.StructuredError)
+			assert.True(t, isStructured)
+			assert.Equal(t, result.code, status.Code(rebuilt))
+			assert.Equal(t, result.kind, terror.GetKind(rebuilt))
+
+			_, _, additionalInfo := terror.GetLoggingInfo(rebuilt)
+			assert.Equal(t, result.additionalInfo, additionalInfo.ToJSON())
+
+			assertFunc(t)
+		})
+	}
+}
+
+// TestDefaultInfo isn't parallel, as the defaults are global, so the parallel tests wait for it
+func TestDefaultInfo(t *testing.T) {
+	// Arrange
+	t.Cleanup(terror.ClearDefaultInfo)
+	terror.SetDefaultInfo(terror.WithStringInfo("service", "server"), terror.WithStringInfo("revision", "abc123"))
+	err := terror.New(nil, fmt.Errorf("user not found"), terror.KindKey.Info(terror.KindNotFound), terror.WithStringInfo("user_id", "1"))
+
+	// Act
+	st := ToStatus(err)
+	// The client has defaults of its own
+	terror.ClearDefaultInfo()
+	terror.SetDefaultInfo(terror.WithStringInfo("service", "client"))
+	rebuilt := FromError(nil, st.Err())
+
+	// Assert
+	// The server's defaults aren't sent, so they don't outrank the client's own
+	assert.Equal(t, map[string]string{"kind": "not_found", "user_id": "1"}, getErrorInfo(st).GetMetadata())
+
+	_, _, additionalInfo := terror.GetLoggingInfo(rebuilt)
+	assert.Equal(t, map[string]any{
+		"service": "client",
+		"kind":    terror.KindNotFound,
+		"user_id": "1",
+	}, additionalInfo.ToJSON())
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err     error
+		options []Option
+	}
+	type result struct {
+		code           codes.Code
+		kind           terror.Kind
+		message        string
+		additionalInfo map[string]any
+		resourceInfo   bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with structured error",
+			args: &args{
+				err:     terror.New(nil, fmt.Errorf("database down"), terror.KindKey.Info(terror.KindUnavailable), terror.WithStringInfo("database", "users")),
+				options: []Option{},
+			},
+			result: &result{
+				code:    codes.Unavailable,
+				kind:    terror.KindUnavailable,
+				message: "database down",
+				additionalInfo: map[string]any{
+					"kind":     terror.KindUnavailable,
+					"database": "users",
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with structured error and debug info",
+			args: &args{
+				err:     terror.New(nil, fmt.Errorf("database down"), terror.KindKey.Info(terror.KindUnavailable), terror.WithStringInfo("database", "users")),
+				options: []Option{WithDebugInfo()},
+			},
+			result: &result{
+				code:    codes.Unavailable,
+				kind:    terror.KindUnavailable,
+				message: "database down",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					_, serverCallstack, _ := terror.GetLoggingInfo(args.err)
+					result.additionalInfo = map[string]any{
+						"kind":             terror.KindUnavailable,
+						"database":         "users",
+						RemoteCallstackKey: strings.Split(serverCallstack, "\n"),
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with status error",
+			args: &args{
+				options: []Option{},
+			},
+			result: &result{
+				code:    codes.NotFound,
+				kind:    terror.KindNotFound,
+				message: "user missing",
+				additionalInfo: map[string]any{
+					"kind": terror.KindNotFound,
+				},
+				resourceInfo: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					args.err = newStatusError(t)
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			listener := bufconn.Listen(1024 * 1024)
+			server := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor(args.options...)))
+			grpc_health_v1.RegisterHealthServer(server, &testHealthServer{err: args.err})
+			go func() {
+				_ = server.Serve(listener)
+			}()
+			defer server.Stop()
+
+			conn, err := grpc.NewClient("passthrough:///bufconn",
+				grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+					return listener.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+				grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+			)
+			assert.NoError(t, err)
+			defer func() {
+				_ = conn.Close()
+			}()
+
+			// Act
+			actFunc(t)
+			_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+			// Assert
+			_, isStructured := err.(*terror.StructuredError)
+			assert.True(t, isStructured)
+			assert.Equal(t, result.code, status.Code(err))
+			assert.Equal(t, result.kind, terror.GetKind(err))
+
+			_, _, additionalInfo := terror.GetLoggingInfo(err)
+			assert.Equal(t, result.additionalInfo, additionalInfo.ToJSON())
+
+			st, found := getCauseStatus(err)
+			assert.True(t, found)
+			assert.Equal(t, result.message, st.Message())
+			assert.Equal(t, result.resourceInfo, getResourceInfo(st) != nil)
+
+			// Passing the error on to another service doesn't prefix the message again
+			assert.Equal(t, result.message, ToStatus(err).Message())
+
+			assertFunc(t)
+		})
+	}
+}
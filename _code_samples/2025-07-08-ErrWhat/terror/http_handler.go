@@ -0,0 +1,258 @@
+package terror
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+)
+
+// The additional info keys of a RequestContext
+const (
+	MethodKey        = "method"
+	RouteKey         = "route"
+	RequestIDKey     = "request_id"
+	RemoteAddressKey = "remote_address"
+)
+
+var kindStatusCodes = map[Kind]int{
+	KindInvalidArgument:    http.StatusBadRequest,
+	KindNotFound:           http.StatusNotFound,
+	KindAlreadyExists:      http.StatusConflict,
+	KindPermissionDenied:   http.StatusForbidden,
+	KindUnauthenticated:    http.StatusUnauthorized,
+	KindResourceExhausted:  http.StatusTooManyRequests,
+	KindFailedPrecondition: http.StatusBadRequest,
+	KindAborted:            http.StatusConflict,
+	KindUnavailable:        http.StatusServiceUnavailable,
+	KindDeadlineExceeded:   http.StatusGatewayTimeout,
+	// Client Closed Request, as used by nginx and grpc-gateway
+	KindCanceled:      499,
+	KindUnimplemented: http.StatusNotImplemented,
+	KindInternal:      http.StatusInternalServerError,
+}
+
+// RequestContext is the StructuredContext of a request served by NewHandler, errors created with it
+// carry the request's method, route, request ID and remote address
+type RequestContext struct {
+	context.Context
+
+	additionalInfo AdditionalInfos
+}
+
+func (instance *RequestContext) GetAdditionalInfo() (additionalInfo AdditionalInfos) {
+	return instance.additionalInfo
+}
+
+type requestContextKey struct{}
+
+// GetRequestContext returns the RequestContext of a request served by NewHandler, or a new one for
+// any other request
+func GetRequestContext(request *http.Request) (requestContext *RequestContext) {
+	requestContext, ok := request.Context().Value(requestContextKey{}).(*RequestContext)
+	if ok {
+		return requestContext
+	}
+
+	return newRequestContext(request, request.Header.Get("X-Request-Id"))
+}
+
+func newRequestContext(request *http.Request, requestID string) (requestContext *RequestContext) {
+	propagated := NewHTTPHeaderContext(request.Context(), request.Header)
+
+	// The mux pattern rather than the path, so the route has a few distinct values
+	route := request.Pattern
+	if route == "" {
+		route = request.URL.Path
+	}
+
+	additionalInfo := append(propagated.GetAdditionalInfo(),
+		WithStringInfo(MethodKey, request.Method),
+		WithStringInfo(RouteKey, route),
+		WithStringInfo(RemoteAddressKey, request.RemoteAddr),
+	)
+	if requestID != "" {
+		additionalInfo = append(additionalInfo, WithStringInfo(RequestIDKey, requestID))
+	}
+
+	requestContext = &RequestContext{
+		additionalInfo: additionalInfo,
+	}
+	requestContext.Context = context.WithValue(propagated, requestContextKey{}, requestContext)
+
+	return requestContext
+}
+
+// HandlerFunc is an http handler which returns its error, for NewHandler to write, rather than
+// writing the error response itself
+type HandlerFunc func(writer http.ResponseWriter, request *http.Request) (err error)
+
+type handlerOptions struct {
+	report          func(ctx context.Context, err error)
+	statusCodes     map[Kind]int
+	requestIDHeader string
+}
+
+type HandlerOption func(options *handlerOptions)
+
+// WithHandlerSink sets where errors are reported, e.g. a Sink's Report, each error is reported
+// once. The default logs PrintErrorLine with slog, at the level of its Severity.
+func WithHandlerSink(report func(ctx context.Context, err error)) (option HandlerOption) {
+	return func(options *handlerOptions) {
+		options.report = report
+	}
+}
+
+// WithStatusCode maps the kind to the status code, adding to or replacing the mapping of the
+// well-known kinds
+func WithStatusCode(kind Kind, statusCode int) (option HandlerOption) {
+	return func(options *handlerOptions) {
+		options.statusCodes[kind] = statusCode
+	}
+}
+
+// WithRequestIDHeader sets the header the request ID is read from and written to, the default is
+// X-Request-Id
+func WithRequestIDHeader(header string) (option HandlerOption) {
+	return func(options *handlerOptions) {
+		options.requestIDHeader = header
+	}
+}
+
+type errorHandler struct {
+	handlerFunc HandlerFunc
+	options     *handlerOptions
+}
+
+// NewHandler adapts the HandlerFunc to an http.Handler. Requests are given a RequestContext, see
+// GetRequestContext, with the request ID taken from the request or generated. Panics are recovered
+// into errors of KindInternal. Errors are reported to the sink, and written as a problem+json body
+// with the status code mapped from their kind, unless the handler already wrote a response.
+func NewHandler(handlerFunc HandlerFunc, options ...HandlerOption) (handler http.Handler) {
+	resolved := &handlerOptions{
+		report: func(ctx context.Context, err error) {
+			slog.Log(ctx, GetSeverity(err).SlogLevel(), PrintErrorLine(err))
+		},
+		statusCodes:     maps.Clone(kindStatusCodes),
+		requestIDHeader: "X-Request-Id",
+	}
+	for _, option := range options {
+		option(resolved)
+	}
+
+	return &errorHandler{
+		handlerFunc: handlerFunc,
+		options:     resolved,
+	}
+}
+
+func (instance *errorHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	requestID := request.Header.Get(instance.options.requestIDHeader)
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	writer.Header().Set(instance.options.requestIDHeader, requestID)
+
+	requestContext := newRequestContext(request, requestID)
+	request = request.WithContext(requestContext)
+	trackingWriter := &trackingResponseWriter{ResponseWriter: writer}
+
+	err := instance.serve(requestContext, trackingWriter, request)
+	if err == nil {
+		return
+	}
+
+	// Wrap with the request context, so errors created without it still carry the request info
+	err = New(requestContext, err)
+	instance.options.report(requestContext, err)
+
+	if !trackingWriter.wroteHeader {
+		instance.writeProblem(writer, err, requestID)
+	}
+}
+
+func (instance *errorHandler) serve(requestContext *RequestContext, writer http.ResponseWriter, request *http.Request) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		// net/http's sentinel for aborting a response
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+
+		recoveredErr, ok := recovered.(error)
+		if !ok {
+			recoveredErr = fmt.Errorf("%v", recovered)
+		}
+		err = New(requestContext, fmt.Errorf("panic: %w", recoveredErr), KindKey.Info(KindInternal))
+	}()
+
+	return instance.handlerFunc(writer, request)
+}
+
+// writeProblem writes an RFC 9457 problem details body, the cause is only shown for client errors,
+// as server errors may expose internals
+func (instance *errorHandler) writeProblem(writer http.ResponseWriter, err error, requestID string) {
+	kind := GetKind(err)
+	statusCode, found := instance.options.statusCodes[kind]
+	if !found {
+		statusCode = http.StatusInternalServerError
+	}
+
+	problem := map[string]any{
+		"type":       "about:blank",
+		"title":      http.StatusText(statusCode),
+		"status":     statusCode,
+		"request_id": requestID,
+	}
+	if kind != "" {
+		problem["kind"] = kind
+	}
+	id, found := GetErrorID(err)
+	if found {
+		problem["error_id"] = id
+	}
+	if statusCode < http.StatusInternalServerError {
+		problem["detail"] = err.Error()
+	}
+
+	writer.Header().Set("Content-Type", "application/problem+json")
+	writer.WriteHeader(statusCode)
+	_ = json.NewEncoder(writer).Encode(problem)
+}
+
+func newRequestID() (requestID string) {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id) // #nosec G104 docs say this never returns an error
+
+	return hex.EncodeToString(id)
+}
+
+// trackingResponseWriter records whether the handler has started the response
+type trackingResponseWriter struct {
+	http.ResponseWriter
+
+	wroteHeader bool
+}
+
+func (instance *trackingResponseWriter) WriteHeader(statusCode int) {
+	instance.wroteHeader = true
+	instance.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (instance *trackingResponseWriter) Write(data []byte) (n int, err error) {
+	instance.wroteHeader = true
+
+	return instance.ResponseWriter.Write(data)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer
+func (instance *trackingResponseWriter) Unwrap() (writer http.ResponseWriter) {
+	return instance.ResponseWriter
+}
@@ -0,0 +1,271 @@
+package terror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		handlerFunc HandlerFunc
+		requestID   string
+		options     []HandlerOption
+	}
+	type result struct {
+		statusCode     int
+		problem        map[string]any
+		additionalInfo map[string]any
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "without error",
+			args: &args{
+				handlerFunc: func(writer http.ResponseWriter, request *http.Request) (err error) {
+					_, _ = writer.Write([]byte("ok"))
+					return nil
+				},
+				requestID: "request-1",
+				options:   []HandlerOption{},
+			},
+			result: &result{
+				statusCode: http.StatusOK,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with kind, created with the request context",
+			args: &args{
+				handlerFunc: func(writer http.ResponseWriter, request *http.Request) (err error) {
+					return New(GetRequestContext(request), fmt.Errorf("user not found"), KindKey.Info(KindNotFound))
+				},
+				requestID: "request-1",
+				options:   []HandlerOption{},
+			},
+			result: &result{
+				statusCode: http.StatusNotFound,
+				problem: map[string]any{
+					"type":       "about:blank",
+					"title":      "Not Found",
+					"status":     float64(http.StatusNotFound),
+					"detail":     "user not found",
+					"kind":       "not_found",
+					"request_id": "request-1",
+					"error_id":   testErrorID,
+				},
+				additionalInfo: map[string]any{
+					MethodKey:        "GET",
+					RouteKey:         "GET /users/{id}",
+					RemoteAddressKey: "192.0.2.1:1234",
+					RequestIDKey:     "request-1",
+					"kind":           KindNotFound,
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with custom kind status code",
+			args: &args{
+				handlerFunc: func(writer http.ResponseWriter, request *http.Request) (err error) {
+					return New(nil, fmt.Errorf("payment required"), KindKey.Info("payment"))
+				},
+				requestID: "request-1",
+				options:   []HandlerOption{WithStatusCode("payment", http.StatusPaymentRequired)},
+			},
+			result: &result{
+				statusCode: http.StatusPaymentRequired,
+				problem: map[string]any{
+					"type":       "about:blank",
+					"title":      "Payment Required",
+					"status":     float64(http.StatusPaymentRequired),
+					"detail":     "payment required",
+					"kind":       "payment",
+					"request_id": "request-1",
+					"error_id":   testErrorID,
+				},
+				additionalInfo: map[string]any{
+					MethodKey:        "GET",
+					RouteKey:         "GET /users/{id}",
+					RemoteAddressKey: "192.0.2.1:1234",
+					RequestIDKey:     "request-1",
+					"kind":           Kind("payment"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with standard error, hiding the cause",
+			args: &args{
+				handlerFunc: func(writer http.ResponseWriter, request *http.Request) (err error) {
+					return errors.New("database password rejected")
+				},
+				requestID: "request-1",
+				options:   []HandlerOption{},
+			},
+			result: &result{
+				statusCode: http.StatusInternalServerError,
+				problem: map[string]any{
+					"type":       "about:blank",
+					"title":      "Internal Server Error",
+					"status":     float64(http.StatusInternalServerError),
+					"request_id": "request-1",
+					"error_id":   testErrorID,
+				},
+				additionalInfo: map[string]any{
+					MethodKey:        "GET",
+					RouteKey:         "GET /users/{id}",
+					RemoteAddressKey: "192.0.2.1:1234",
+					RequestIDKey:     "request-1",
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with panic",
+			args: &args{
+				handlerFunc: func(writer http.ResponseWriter, request *http.Request) (err error) {
+					panic("nil map")
+				},
+				requestID: "request-1",
+				options:   []HandlerOption{},
+			},
+			result: &result{
+				statusCode: http.StatusInternalServerError,
+				problem: map[string]any{
+					"type":       "about:blank",
+					"title":      "Internal Server Error",
+					"status":     float64(http.StatusInternalServerError),
+					"kind":       "internal",
+					"request_id": "request-1",
+					"error_id":   testErrorID,
+				},
+				additionalInfo: map[string]any{
+					MethodKey:        "GET",
+					RouteKey:         "GET /users/{id}",
+					RemoteAddressKey: "192.0.2.1:1234",
+					RequestIDKey:     "request-1",
+					"kind":           KindInternal,
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with response already written",
+			args: &args{
+				handlerFunc: func(writer http.ResponseWriter, request *http.Request) (err error) {
+					writer.WriteHeader(http.StatusAccepted)
+					return New(GetRequestContext(request), fmt.Errorf("stream interrupted"))
+				},
+				requestID: "request-1",
+				options:   []HandlerOption{},
+			},
+			result: &result{
+				statusCode: http.StatusAccepted,
+				additionalInfo: map[string]any{
+					MethodKey:        "GET",
+					RouteKey:         "GET /users/{id}",
+					RemoteAddressKey: "192.0.2.1:1234",
+					RequestIDKey:     "request-1",
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			sink := NewMemorySink()
+			options := append([]HandlerOption{WithHandlerSink(sink.Report)}, args.options...)
+			mux := http.NewServeMux()
+			mux.Handle("GET /users/{id}", NewHandler(args.handlerFunc, options...))
+
+			request := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+			request.Header.Set("X-Request-Id", args.requestID)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			actFunc(t)
+			mux.ServeHTTP(recorder, request)
+
+			// Assert
+			assert.Equal(t, result.statusCode, recorder.Code)
+			assert.Equal(t, args.requestID, recorder.Header().Get("X-Request-Id"))
+
+			if result.problem != nil {
+				assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+				problem := map[string]any{}
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+				assert.Equal(t, result.problem, problem)
+			}
+
+			if result.additionalInfo == nil {
+				assert.Empty(t, sink.Errors())
+			} else {
+				assert.Len(t, sink.Errors(), 1)
+				_, _, additionalInfo := GetLoggingInfo(sink.Errors()[0])
+				assert.Equal(t, result.additionalInfo, additionalInfo.ToJSON())
+			}
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestNewHandlerRequestID(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	sink := NewMemorySink()
+	handler := NewHandler(func(writer http.ResponseWriter, request *http.Request) (err error) {
+		return errors.New("standard error")
+	}, WithHandlerSink(sink.Report), WithRequestIDHeader("X-Correlation-Id"))
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Assert
+	requestID := recorder.Header().Get("X-Correlation-Id")
+	assert.Len(t, requestID, 16)
+	assert.Len(t, sink.Errors(), 1)
+
+	requestIDInfo, found := Lookup[string](sink.Errors()[0], RequestIDKey)
+	assert.True(t, found)
+	assert.Equal(t, requestID, requestIDInfo)
+}
@@ -0,0 +1,29 @@
+package terror
+
+// Key is a typed AdditionalInfo key, declare them once in a registry and share them, so every team
+// writes "user_id" the same way and the compiler checks the value type.
+//
+//	var UserID = terror.NewKey[string]("user_id")
+//
+//	err = terror.New(ctx, err, UserID.Info(user.ID))
+//	userID, found := UserID.Lookup(err)
+type Key[T jsonValue] struct {
+	name string
+}
+
+func NewKey[T jsonValue](name string) (key Key[T]) {
+	return Key[T]{name: name}
+}
+
+func (instance Key[T]) Name() (name string) {
+	return instance.name
+}
+
+func (instance Key[T]) Info(value T) (info AdditionalInfo) {
+	return typedInfo[T]{key: instance.name, value: value}
+}
+
+// Lookup returns the value of the key from the additional info of the error tree, see Lookup.
+func (instance Key[T]) Lookup(err error) (value T, found bool) {
+	return Lookup[T](err, instance.name)
+}
@@ -0,0 +1,234 @@
+package terror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUserID string
+
+func TestKeyInfo(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T) (instance AdditionalInfo)
+	type assertFunc func(t *testing.T)
+	type args struct{}
+	type result struct {
+		info AdditionalInfo
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with string key",
+			args: &args{},
+			result: &result{
+				info: WithStringInfo("user_id", "user"),
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) (instance AdditionalInfo) {
+					return NewKey[string]("user_id").Info("user")
+				}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with int key",
+			args: &args{},
+			result: &result{
+				info: WithIntInfo("attempt", 3),
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) (instance AdditionalInfo) {
+					return NewKey[int64]("attempt").Info(3)
+				}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with named string type key",
+			args: &args{},
+			result: &result{
+				info: WithStringInfo("user_id", testUserID("user")),
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) (instance AdditionalInfo) {
+					return NewKey[testUserID]("user_id").Info("user")
+				}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			instance := actFunc(t)
+
+			// Assert
+			assert.Equal(t, result.info, instance)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestKeyLookup(t *testing.T) {
+	t.Parallel()
+
+	userID := NewKey[string]("user_id")
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+	}
+	type result struct {
+		value string
+		found bool
+	}
+	type testConfig struct {
+		name          string
+		instance      Key[string]
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "returns the value from the error",
+			instance: userID,
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), userID.Info("user")),
+			},
+			result: &result{
+				value: "user",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns the value from the context",
+			instance: userID,
+			args: &args{
+				err: New(&testContext{
+					additionalInfo: AdditionalInfos{userID.Info("user")},
+				}, fmt.Errorf("root error")),
+			},
+			result: &result{
+				value: "user",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns the deepest value, as with Flatten",
+			instance: userID,
+			args: &args{
+				err: New(nil, New(nil, fmt.Errorf("root error"), userID.Info("inner")), userID.Info("outer")),
+			},
+			result: &result{
+				value: "inner",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns the value when wrapped with errors.Join",
+			instance: userID,
+			args: &args{
+				err: errors.Join(New(nil, fmt.Errorf("root error"), userID.Info("user"))),
+			},
+			result: &result{
+				value: "user",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns not found when the key is missing",
+			instance: userID,
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), WithStringInfo("userId", "user")),
+			},
+			result: &result{
+				value: "",
+				found: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns not found when the value has a different type",
+			instance: userID,
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), WithIntInfo("user_id", 1)),
+			},
+			result: &result{
+				value: "",
+				found: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns not found for a standard go error",
+			instance: userID,
+			args: &args{
+				err: fmt.Errorf("root error"),
+			},
+			result: &result{
+				value: "",
+				found: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			value, found := instance.Lookup(args.err)
+
+			// Assert
+			assert.Equal(t, result.value, value)
+			assert.Equal(t, result.found, found)
+
+			assertFunc(t)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package terror
+
+import "reflect"
+
+// Kind classifies an error for handling at the boundaries, e.g. choosing a status code or deciding
+// whether to retry, without comparing against every error it could wrap
+type Kind string
+
+// The well-known kinds, which the transport packages map to status codes, named after the gRPC codes
+const (
+	KindInvalidArgument    Kind = "invalid_argument"
+	KindNotFound           Kind = "not_found"
+	KindAlreadyExists      Kind = "already_exists"
+	KindPermissionDenied   Kind = "permission_denied"
+	KindUnauthenticated    Kind = "unauthenticated"
+	KindResourceExhausted  Kind = "resource_exhausted"
+	KindFailedPrecondition Kind = "failed_precondition"
+	KindAborted            Kind = "aborted"
+	KindUnavailable        Kind = "unavailable"
+	KindDeadlineExceeded   Kind = "deadline_exceeded"
+	KindCanceled           Kind = "canceled"
+	KindUnimplemented      Kind = "unimplemented"
+	KindInternal           Kind = "internal"
+)
+
+// KindKey records the Kind as additional info, so the deepest layer to set it takes precedence
+//
+//	err = terror.New(ctx, err, terror.KindKey.Info("not_found"))
+var KindKey = NewKey[Kind]("kind")
+
+// GetKind returns the Kind of the error, or an empty Kind when none was set. Any string value of the
+// key is a Kind, so WithStringInfo("kind", "not_found") is the same as KindKey.Info(KindNotFound).
+func GetKind(err error) (kind Kind) {
+	value, found := Lookup[any](err, KindKey.Name())
+	if !found {
+		return ""
+	}
+
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.String {
+		return ""
+	}
+
+	return Kind(reflected.String())
+}
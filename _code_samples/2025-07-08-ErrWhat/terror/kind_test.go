@@ -0,0 +1,117 @@
+package terror
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKind(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+	}
+	type result struct {
+		kind Kind
+	}
+	type testConfig struct {
+		name          string
+		instance      error
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "returns the kind",
+			instance: New(nil, fmt.Errorf("root error"), KindKey.Info("not_found")),
+			args:     &args{},
+			result: &result{
+				kind: "not_found",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns the deepest kind",
+			instance: New(nil, New(nil, fmt.Errorf("root error"), KindKey.Info("not_found")), KindKey.Info("internal")),
+			args:     &args{},
+			result: &result{
+				kind: "not_found",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns a kind set as a string",
+			instance: New(nil, fmt.Errorf("root error"), WithStringInfo("kind", "not_found")),
+			args:     &args{},
+			result: &result{
+				kind: KindNotFound,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns an empty kind when it isn't a string",
+			instance: New(nil, fmt.Errorf("root error"), WithIntInfo("kind", 404)),
+			args:     &args{},
+			result: &result{
+				kind: "",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns an empty kind when none was set",
+			instance: New(nil, fmt.Errorf("root error")),
+			args:     &args{},
+			result: &result{
+				kind: "",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns an empty kind for a standard go error",
+			instance: fmt.Errorf("root error"),
+			args:     &args{},
+			result: &result{
+				kind: "",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			kind := GetKind(instance)
+
+			// Assert
+			assert.Equal(t, result.kind, kind)
+
+			assertFunc(t)
+		})
+	}
+}
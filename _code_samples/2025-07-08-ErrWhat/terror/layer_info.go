@@ -0,0 +1,120 @@
+package terror
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	captureTime      atomic.Bool
+	captureGoroutine atomic.Bool
+)
+
+// SetCaptureTime sets whether New records when each layer is created, it's off by default
+func SetCaptureTime(enabled bool) {
+	captureTime.Store(enabled)
+}
+
+// SetCaptureGoroutine sets whether New records the ID of the goroutine each layer is created on,
+// it's off by default as reading the ID from the stack header costs a runtime.Stack call per layer
+func SetCaptureGoroutine(enabled bool) {
+	captureGoroutine.Store(enabled)
+}
+
+// LayerInfo is when and on which goroutine a layer of a StructuredError was created
+type LayerInfo struct {
+	// Time is zero unless SetCaptureTime was enabled
+	Time time.Time
+	// GoroutineID is 0 unless SetCaptureGoroutine was enabled
+	GoroutineID uint64
+}
+
+func newLayerInfo() (layerInfo LayerInfo) {
+	if captureTime.Load() {
+		layerInfo.Time = time.Now()
+	}
+	if captureGoroutine.Load() {
+		layerInfo.GoroutineID = getGoroutineID()
+	}
+
+	return layerInfo
+}
+
+// getGoroutineID parses the ID from the stack header, e.g. "goroutine 18 [running]:"
+func getGoroutineID() (goroutineID uint64) {
+	buffer := make([]byte, 64)
+	buffer = buffer[:runtime.Stack(buffer, false)]
+	buffer = bytes.TrimPrefix(buffer, []byte("goroutine "))
+	end := bytes.IndexByte(buffer, ' ')
+	if end < 0 {
+		return 0
+	}
+
+	goroutineID, err := strconv.ParseUint(string(buffer[:end]), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return goroutineID
+}
+
+// GetLayerInfo returns the LayerInfo of each StructuredError in the chain, from the outermost
+// inwards, so the last is where the error originated
+func GetLayerInfo(err error) (layers []LayerInfo) {
+	layers = make([]LayerInfo, 0)
+	current, _ := err.(*StructuredError)
+	for current != nil {
+		layers = append(layers, current.layerInfo)
+		current, _ = current.cause.(*StructuredError)
+	}
+
+	return layers
+}
+
+// GetPropagationTime returns how long the error took to be wrapped by its outermost layer after it
+// originated, found is false unless both times were recorded
+func GetPropagationTime(err error) (duration time.Duration, found bool) {
+	layers := GetLayerInfo(err)
+	if len(layers) == 0 {
+		return 0, false
+	}
+
+	outermost := layers[0].Time
+	origin := layers[len(layers)-1].Time
+	if outermost.IsZero() || origin.IsZero() {
+		return 0, false
+	}
+
+	return outermost.Sub(origin), true
+}
+
+// formatOrigin describes when and where the error originated for PrintError, empty if neither was
+// recorded
+func formatOrigin(err error) (origin string) {
+	layers := GetLayerInfo(err)
+	if len(layers) == 0 {
+		return ""
+	}
+
+	layerInfo := layers[len(layers)-1]
+	switch {
+	case !layerInfo.Time.IsZero() && layerInfo.GoroutineID != 0:
+		origin = layerInfo.Time.Format(time.RFC3339Nano) + " on goroutine " + strconv.FormatUint(layerInfo.GoroutineID, 10)
+	case !layerInfo.Time.IsZero():
+		origin = layerInfo.Time.Format(time.RFC3339Nano)
+	case layerInfo.GoroutineID != 0:
+		origin = "goroutine " + strconv.FormatUint(layerInfo.GoroutineID, 10)
+	default:
+		return ""
+	}
+
+	propagationTime, found := GetPropagationTime(err)
+	if found && len(layers) > 1 {
+		origin += ", propagated in " + propagationTime.String()
+	}
+
+	return origin
+}
@@ -0,0 +1,159 @@
+package terror
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLayerInfo isn't parallel, as capturing is global, so the parallel tests wait for it
+func TestLayerInfo(t *testing.T) {
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		captureTime      bool
+		captureGoroutine bool
+	}
+	type result struct {
+		recordedTime      bool
+		recordedGoroutine bool
+		created           string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "with time and goroutine",
+			args:   &args{captureTime: true, captureGoroutine: true},
+			result: &result{recordedTime: true, recordedGoroutine: true, created: " on goroutine "},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with goroutine only",
+			args:   &args{captureTime: false, captureGoroutine: true},
+			result: &result{recordedTime: false, recordedGoroutine: true, created: "Created: goroutine "},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "without capturing",
+			args:   &args{captureTime: false, captureGoroutine: false},
+			result: &result{recordedTime: false, recordedGoroutine: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			SetCaptureTime(args.captureTime)
+			SetCaptureGoroutine(args.captureGoroutine)
+			t.Cleanup(func() {
+				SetCaptureTime(false)
+				SetCaptureGoroutine(false)
+			})
+
+			// Act
+			actFunc(t)
+			errs := make(chan error)
+			go func() {
+				errs <- New(nil, fmt.Errorf("root error"))
+			}()
+			origin := <-errs
+			time.Sleep(time.Millisecond)
+			err := New(nil, origin)
+
+			// Assert
+			layers := GetLayerInfo(err)
+			assert.Len(t, layers, 2)
+			assert.Equal(t, result.recordedTime, !layers[1].Time.IsZero())
+			assert.Equal(t, result.recordedGoroutine, layers[1].GoroutineID != 0)
+
+			propagationTime, found := GetPropagationTime(err)
+			assert.Equal(t, result.recordedTime, found)
+
+			printed := PrintError(err)
+			rendered, templateErr := PrintErrorTemplate(err, TextTemplate)
+			assert.NoError(t, templateErr)
+			pretty := PrintErrorPretty(err, WithColor(false))
+			if result.created == "" {
+				assert.NotContains(t, printed, "Created:")
+				assert.NotContains(t, rendered, "Created:")
+				assert.NotContains(t, pretty, "Created:")
+			} else {
+				assert.Contains(t, printed, result.created)
+				// The template and PrintErrorPretty render the same line as PrintError
+				createdLine := printed[strings.Index(printed, "Created: "):]
+				createdLine = createdLine[:strings.Index(createdLine, "\n")+1]
+				assert.Contains(t, rendered, createdLine)
+				assert.Contains(t, pretty, createdLine)
+			}
+
+			if result.recordedTime {
+				assert.GreaterOrEqual(t, propagationTime, time.Millisecond)
+				assert.Contains(t, printed, ", propagated in ")
+				record := NewLogRecord(err)
+				assert.Equal(t, layers[1].Time, record.Timestamp())
+			}
+			if result.recordedGoroutine {
+				// The error crossed goroutines
+				assert.NotEqual(t, layers[0].GoroutineID, layers[1].GoroutineID)
+				assert.Equal(t, getGoroutineID(), layers[0].GoroutineID)
+
+				data := NewTemplateData(err)
+				assert.Equal(t, layers[1].GoroutineID, data.Layers[1].GoroutineID)
+			}
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestGetGoroutineID(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	goroutineID := getGoroutineID()
+	otherGoroutineIDs := make(chan uint64)
+	go func() {
+		otherGoroutineIDs <- getGoroutineID()
+	}()
+
+	// Assert
+	assert.NotZero(t, goroutineID)
+	assert.NotEqual(t, goroutineID, <-otherGoroutineIDs)
+}
+
+func TestGetLayerInfoStandardError(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	err := fmt.Errorf("standard error")
+
+	// Act
+	layers := GetLayerInfo(err)
+	_, found := GetPropagationTime(err)
+
+	// Assert
+	assert.Empty(t, layers)
+	assert.False(t, found)
+}
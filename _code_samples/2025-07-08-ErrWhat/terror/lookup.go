@@ -0,0 +1,69 @@
+package terror
+
+// Lookup returns the value of key from the additional info of every StructuredError in the error
+// tree, descending through fmt.Errorf and errors.Join wrappers. Precedence matches Flatten, so deeper
+// in the chain wins, and where errors are joined the later error wins.
+// If the key is missing, or the value was stored with a different type, found is false.
+func Lookup[T any](err error, key string) (value T, found bool) {
+	walkErrorTree(err, make(map[StructuredContext]bool), func(infos AdditionalInfos) {
+		for _, info := range infos {
+			if info.GetKey() != key {
+				continue
+			}
+
+			value, found = info.GetValue().(T)
+		}
+	})
+
+	return value, found
+}
+
+// LookupAll returns every value of key in the error tree with the type T, in precedence order, so the
+// last value is the one Lookup would return.
+func LookupAll[T any](err error, key string) (values []T) {
+	values = make([]T, 0)
+	walkErrorTree(err, make(map[StructuredContext]bool), func(infos AdditionalInfos) {
+		for _, info := range infos {
+			if info.GetKey() != key {
+				continue
+			}
+
+			value, ok := info.GetValue().(T)
+			if ok {
+				values = append(values, value)
+			}
+		}
+	})
+
+	return values
+}
+
+// walkErrorTree visits the additional info of each StructuredError in the tree in the order errors.As
+// would find them. StructuredError doesn't unwrap, so the cause of the innermost layer is walked here
+// to find StructuredErrors wrapped by other types of error.
+func walkErrorTree(err error, visitedContexts map[StructuredContext]bool, visit func(infos AdditionalInfos)) {
+	switch e := err.(type) {
+	case nil:
+		return
+	case *StructuredError:
+		e.walkAdditionalInfo(visitedContexts, visit)
+
+		cause := e.cause
+		for {
+			structuredError, ok := cause.(*StructuredError)
+			if !ok {
+				break
+			}
+			cause = structuredError.cause
+		}
+		walkErrorTree(cause, visitedContexts, visit)
+	case interface{ Unwrap() error }:
+		walkErrorTree(e.Unwrap(), visitedContexts, visit)
+	case interface{ Unwrap() []error }:
+		for _, child := range e.Unwrap() {
+			walkErrorTree(child, visitedContexts, visit)
+		}
+	default:
+		// Not a wrapper, nothing further to traverse
+	}
+}
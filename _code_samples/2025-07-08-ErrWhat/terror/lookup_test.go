@@ -0,0 +1,247 @@
+package terror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+		key string
+	}
+	type result struct {
+		value string
+		found bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "returns the value from a structured error",
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), WithStringInfo("tenant_id", "tenant")),
+				key: "tenant_id",
+			},
+			result: &result{
+				value: "tenant",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns the value when wrapped with fmt.Errorf",
+			args: &args{
+				err: fmt.Errorf("wrapped: %w", New(nil, fmt.Errorf("root error"), WithStringInfo("tenant_id", "tenant"))),
+				key: "tenant_id",
+			},
+			result: &result{
+				value: "tenant",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns the value from a structured error wrapped by the cause of another",
+			args: &args{
+				err: New(nil,
+					fmt.Errorf("wrapped: %w", New(nil, fmt.Errorf("root error"), WithStringInfo("tenant_id", "inner"))),
+					WithStringInfo("tenant_id", "outer"),
+				),
+				key: "tenant_id",
+			},
+			result: &result{
+				value: "inner",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns the value from the later error when joined",
+			args: &args{
+				err: errors.Join(
+					New(nil, fmt.Errorf("first error"), WithStringInfo("tenant_id", "first")),
+					fmt.Errorf("second error"),
+					New(nil, fmt.Errorf("third error"), WithStringInfo("tenant_id", "third")),
+				),
+				key: "tenant_id",
+			},
+			result: &result{
+				value: "third",
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns not found when the value has a different type",
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), WithIntInfo("tenant_id", 1)),
+				key: "tenant_id",
+			},
+			result: &result{
+				value: "",
+				found: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns not found for a standard go error",
+			args: &args{
+				err: fmt.Errorf("root error"),
+				key: "tenant_id",
+			},
+			result: &result{
+				value: "",
+				found: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns not found for a nil error",
+			args: &args{
+				err: nil,
+				key: "tenant_id",
+			},
+			result: &result{
+				value: "",
+				found: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			value, found := Lookup[string](args.err, args.key)
+
+			// Assert
+			assert.Equal(t, result.value, value)
+			assert.Equal(t, result.found, found)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestLookupAll(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+		key string
+	}
+	type result struct {
+		values []int64
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "returns every value in precedence order",
+			args: &args{
+				err: New(
+					&testContext{
+						additionalInfo: AdditionalInfos{WithIntInfo("attempt", 1)},
+					},
+					fmt.Errorf("wrapped: %w", New(nil, fmt.Errorf("root error"), WithIntInfo("attempt", 3))),
+					WithIntInfo("attempt", 2),
+				),
+				key: "attempt",
+			},
+			result: &result{
+				values: []int64{1, 2, 3},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "skips values with a different type",
+			args: &args{
+				err: New(nil, New(nil, fmt.Errorf("root error"), WithStringInfo("attempt", "3")), WithIntInfo("attempt", 2)),
+				key: "attempt",
+			},
+			result: &result{
+				values: []int64{2},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns an empty slice for a standard go error",
+			args: &args{
+				err: fmt.Errorf("root error"),
+				key: "attempt",
+			},
+			result: &result{
+				values: []int64{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			values := LookupAll[int64](args.err, args.key)
+
+			// Assert
+			assert.Equal(t, result.values, values)
+
+			assertFunc(t)
+		})
+	}
+}
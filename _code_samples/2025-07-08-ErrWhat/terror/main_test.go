@@ -0,0 +1,19 @@
+package terror
+
+import (
+	"os"
+	"testing"
+)
+
+const testErrorID = "TEST0000000000000000"
+
+func TestMain(m *testing.M) {
+	// The tests expect exactly the additional info they create, so start without the build info
+	ClearDefaultInfo()
+	// and with the same ID for every error, so the printed errors are deterministic
+	SetIDGenerator(func() (id string) {
+		return testErrorID
+	})
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,132 @@
+package terror
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ToAttribute converts the info to an otel attribute, ok is false for values otel can't represent.
+// otel has no uint64 type, so uint64 values are int64 attributes, unless they're above
+// math.MaxInt64 where they're decimal string attributes instead, as a cast would wrap them
+// negative. A []uint64 with any such value is a string slice, otel slices hold a single type.
+func ToAttribute(info AdditionalInfo) (keyValue attribute.KeyValue, ok bool) {
+	return toAttribute(info.GetKey(), info.GetValue())
+}
+
+// ToAttributes converts the infos to otel attributes, skipping any otel can't represent
+func ToAttributes(infos AdditionalInfos) (attributes []attribute.KeyValue) {
+	attributes = make([]attribute.KeyValue, 0, len(infos))
+	for _, info := range infos {
+		keyValue, ok := ToAttribute(info)
+		if ok {
+			attributes = append(attributes, keyValue)
+		}
+	}
+
+	return attributes
+}
+
+func toAttribute(key string, value any) (keyValue attribute.KeyValue, ok bool) {
+	switch value := value.(type) {
+	case bool:
+		return attribute.Bool(key, value), true
+	case int64:
+		return attribute.Int64(key, value), true
+	case uint64:
+		// otel/attribute doesn't support uint64
+		if value > math.MaxInt64 {
+			return attribute.String(key, strconv.FormatUint(value, 10)), true
+		}
+		return attribute.Int64(key, int64(value)), true
+	case float64:
+		return attribute.Float64(key, value), true
+	case string:
+		return attribute.String(key, value), true
+	case []bool:
+		return attribute.BoolSlice(key, value), true
+	case []int64:
+		return attribute.Int64Slice(key, value), true
+	case []uint64:
+		// otel/attribute doesn't support uint64
+		if hasInt64Overflow(value) {
+			return attribute.StringSlice(key, formatUints(value)), true
+		}
+		values := make([]int64, len(value))
+		for i, v := range value {
+			values[i] = int64(v)
+		}
+		return attribute.Int64Slice(key, values), true
+	case []float64:
+		return attribute.Float64Slice(key, value), true
+	case []string:
+		return attribute.StringSlice(key, value), true
+	default:
+		// Named types, e.g. WithStringInfo("user_id", UserID("...")), convert to their underlying type
+		normalized, normalizedOk := normalizeValue(reflect.ValueOf(value))
+		if !normalizedOk {
+			return keyValue, false
+		}
+
+		return toAttribute(key, normalized)
+	}
+}
+
+// normalizeValue converts a value of a named type to the jsonValue type, or slice of it, underlying it
+func normalizeValue(value reflect.Value) (normalized any, ok bool) {
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool(), true
+	case reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint64:
+		return value.Uint(), true
+	case reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return value.String(), true
+	case reflect.Slice:
+		var elementType reflect.Type
+		switch value.Type().Elem().Kind() {
+		case reflect.Bool:
+			elementType = reflect.TypeFor[bool]()
+		case reflect.Int64:
+			elementType = reflect.TypeFor[int64]()
+		case reflect.Uint64:
+			elementType = reflect.TypeFor[uint64]()
+		case reflect.Float64:
+			elementType = reflect.TypeFor[float64]()
+		case reflect.String:
+			elementType = reflect.TypeFor[string]()
+		default:
+			return nil, false
+		}
+
+		converted := reflect.MakeSlice(reflect.SliceOf(elementType), value.Len(), value.Len())
+		for i := range value.Len() {
+			converted.Index(i).Set(value.Index(i).Convert(elementType))
+		}
+
+		return converted.Interface(), true
+	default:
+		return nil, false
+	}
+}
+
+func hasInt64Overflow(values []uint64) (overflows bool) {
+	return slices.ContainsFunc(values, func(value uint64) bool {
+		return value > math.MaxInt64
+	})
+}
+
+func formatUints(values []uint64) (formatted []string) {
+	formatted = make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = strconv.FormatUint(value, 10)
+	}
+
+	return formatted
+}
@@ -0,0 +1,212 @@
+package terror
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type testTags []string
+
+func TestToAttribute(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		info AdditionalInfo
+	}
+	type result struct {
+		keyValue attribute.KeyValue
+		ok       bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "with bool info",
+			args:   &args{info: WithBoolInfo("one", true)},
+			result: &result{keyValue: attribute.Bool("one", true), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with bool slice info",
+			args:   &args{info: WithBoolSliceInfo("one", []bool{true})},
+			result: &result{keyValue: attribute.BoolSlice("one", []bool{true}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with int info",
+			args:   &args{info: WithIntInfo("one", 1)},
+			result: &result{keyValue: attribute.Int64("one", 1), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with int slice info",
+			args:   &args{info: WithIntSliceInfo("one", []int{1})},
+			result: &result{keyValue: attribute.Int64Slice("one", []int64{1}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with uint info",
+			args:   &args{info: WithUintInfo("one", uint(1))},
+			result: &result{keyValue: attribute.Int64("one", 1), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with uint slice info",
+			args:   &args{info: WithUintSliceInfo("one", []uint{1})},
+			result: &result{keyValue: attribute.Int64Slice("one", []int64{1}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with uint info at max int64",
+			args:   &args{info: WithUintInfo("one", uint64(math.MaxInt64))},
+			result: &result{keyValue: attribute.Int64("one", math.MaxInt64), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with uint info above max int64",
+			args:   &args{info: WithUintInfo("one", uint64(math.MaxInt64)+1)},
+			result: &result{keyValue: attribute.String("one", "9223372036854775808"), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with uint info at max uint64",
+			args:   &args{info: WithUintInfo("one", uint64(math.MaxUint64))},
+			result: &result{keyValue: attribute.String("one", "18446744073709551615"), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with uint slice info at max int64",
+			args:   &args{info: WithUintSliceInfo("one", []uint64{1, math.MaxInt64})},
+			result: &result{keyValue: attribute.Int64Slice("one", []int64{1, math.MaxInt64}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with uint slice info above max int64",
+			args:   &args{info: WithUintSliceInfo("one", []uint64{1, math.MaxInt64 + 1})},
+			result: &result{keyValue: attribute.StringSlice("one", []string{"1", "9223372036854775808"}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with float info",
+			args:   &args{info: WithFloatInfo("one", 1.5)},
+			result: &result{keyValue: attribute.Float64("one", 1.5), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with float slice info",
+			args:   &args{info: WithFloatSliceInfo("one", []float32{1.5})},
+			result: &result{keyValue: attribute.Float64Slice("one", []float64{1.5}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with string info",
+			args:   &args{info: WithStringInfo("one", "string")},
+			result: &result{keyValue: attribute.String("one", "string"), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with string slice info",
+			args:   &args{info: WithStringSliceInfo("one", []string{"string"})},
+			result: &result{keyValue: attribute.StringSlice("one", []string{"string"}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with named string info",
+			args:   &args{info: WithStringInfo("one", testUserID("user"))},
+			result: &result{keyValue: attribute.String("one", "user"), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with named string slice info",
+			args:   &args{info: WithStringSliceInfo("one", []testUserID{"user"})},
+			result: &result{keyValue: attribute.StringSlice("one", []string{"user"}), ok: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with a value otel can't represent",
+			args:   &args{info: testInfo{key: "one", value: map[string]string{}}},
+			result: &result{keyValue: attribute.KeyValue{}, ok: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			keyValue, ok := ToAttribute(args.info)
+
+			// Assert
+			assert.Equal(t, result.keyValue, keyValue)
+			assert.Equal(t, result.ok, ok)
+
+			assertFunc(t)
+		})
+	}
+}
+
+type testInfo struct {
+	key   string
+	value any
+}
+
+func (instance testInfo) GetKey() (key string) {
+	return instance.key
+}
+
+func (instance testInfo) GetValue() (value any) {
+	return instance.value
+}
@@ -0,0 +1,130 @@
+package terror
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewLogRecord converts the error to an otel log record, the body is the cause, the exception.*
+// attributes follow the otel semantic conventions, with a stacktrace for StructuredErrors only, and
+// the flattened additional info follows as typed attributes
+func NewLogRecord(err error) (record log.Record) {
+	cause, callstack, additionalInfo := GetLoggingInfo(err)
+
+	now := time.Now()
+	record.SetTimestamp(now)
+	record.SetObservedTimestamp(now)
+	// The time the error occurred, rather than was logged, when it was recorded
+	layers := GetLayerInfo(err)
+	if len(layers) > 0 && !layers[len(layers)-1].Time.IsZero() {
+		record.SetTimestamp(layers[len(layers)-1].Time)
+	}
+	severity := GetSeverity(err).LogSeverity()
+	record.SetSeverity(severity)
+	record.SetSeverityText(severity.String())
+	record.SetBody(log.StringValue(cause))
+
+	record.AddAttributes(
+		log.String("exception.type", getExceptionType(err)),
+		log.String("exception.message", cause),
+	)
+	structuredError, isStructured := err.(*StructuredError)
+	if isStructured && len(structuredError.getOriginCallstack()) > 0 {
+		record.AddAttributes(log.String("exception.stacktrace", callstack))
+	}
+	if isStructured && structuredError.id != "" {
+		record.AddAttributes(log.String("error.id", structuredError.id))
+	}
+	for _, info := range additionalInfo {
+		value, ok := toLogValue(info.GetValue())
+		if ok {
+			record.AddAttributes(log.KeyValue{Key: info.GetKey(), Value: value})
+		}
+	}
+
+	return record
+}
+
+// EmitError emits the error to the logger. The record is correlated with the span of the innermost
+// StructuredContext carrying one, as that's where the error occurred, otherwise the span of ctx.
+func EmitError(ctx context.Context, logger log.Logger, err error) {
+	structuredError, ok := err.(*StructuredError)
+	for ok {
+		spanContext, isContext := structuredError.context.(context.Context)
+		if isContext && trace.SpanContextFromContext(spanContext).IsValid() {
+			ctx = spanContext
+		}
+
+		structuredError, ok = structuredError.cause.(*StructuredError)
+	}
+
+	logger.Emit(ctx, NewLogRecord(err))
+}
+
+// getExceptionType is the type of the innermost cause, the error the StructuredErrors wrap
+func getExceptionType(err error) (exceptionType string) {
+	structuredError, ok := err.(*StructuredError)
+	for ok {
+		err = structuredError.cause
+		structuredError, ok = err.(*StructuredError)
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+func toLogValue(value any) (logValue log.Value, ok bool) {
+	switch value := value.(type) {
+	case bool:
+		return log.BoolValue(value), true
+	case int64:
+		return log.Int64Value(value), true
+	case uint64:
+		// otel/log doesn't support uint64, see ToAttribute
+		if value > math.MaxInt64 {
+			return log.StringValue(strconv.FormatUint(value, 10)), true
+		}
+		return log.Int64Value(int64(value)), true
+	case float64:
+		return log.Float64Value(value), true
+	case string:
+		return log.StringValue(value), true
+	case []bool:
+		return toLogSliceValue(value, log.BoolValue), true
+	case []int64:
+		return toLogSliceValue(value, log.Int64Value), true
+	case []uint64:
+		// otel/log doesn't support uint64, see ToAttribute
+		if hasInt64Overflow(value) {
+			return toLogSliceValue(formatUints(value), log.StringValue), true
+		}
+		return toLogSliceValue(value, func(v uint64) log.Value { return log.Int64Value(int64(v)) }), true
+	case []float64:
+		return toLogSliceValue(value, log.Float64Value), true
+	case []string:
+		return toLogSliceValue(value, log.StringValue), true
+	default:
+		// Named types convert to their underlying type
+		normalized, normalizedOk := normalizeValue(reflect.ValueOf(value))
+		if !normalizedOk {
+			return logValue, false
+		}
+
+		return toLogValue(normalized)
+	}
+}
+
+func toLogSliceValue[T any](values []T, convert func(value T) log.Value) (logValue log.Value) {
+	logValues := make([]log.Value, len(values))
+	for i, value := range values {
+		logValues[i] = convert(value)
+	}
+
+	return log.SliceValue(logValues...)
+}
@@ -0,0 +1,294 @@
+package terror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testLogger struct {
+	embedded.Logger
+	lock     sync.Mutex
+	contexts []context.Context
+	records  []log.Record
+}
+
+func (instance *testLogger) Emit(ctx context.Context, record log.Record) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	instance.contexts = append(instance.contexts, ctx)
+	instance.records = append(instance.records, record)
+}
+
+func (instance *testLogger) Enabled(ctx context.Context, param log.EnabledParameters) (enabled bool) {
+	return true
+}
+
+func getLogAttributes(record log.Record) (attributes map[string]log.Value) {
+	attributes = make(map[string]log.Value)
+	record.WalkAttributes(func(keyValue log.KeyValue) bool {
+		attributes[keyValue.Key] = keyValue.Value
+		return true
+	})
+
+	return attributes
+}
+
+func newSpanContext(traceID byte, spanID byte) (spanContext trace.SpanContext) {
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{traceID},
+		SpanID:     trace.SpanID{spanID},
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func TestNewLogRecord(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+	}
+	type result struct {
+		severity   log.Severity
+		body       string
+		attributes map[string]log.Value
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with structured error",
+			args: &args{
+				err: New(&testContext{
+					additionalInfo: AdditionalInfos{WithStringInfo("tenant", "acme")},
+				}, New(nil, fmt.Errorf("root error"), WithIntInfo("attempt", 2)), WithUintSliceInfo("shards", []uint{1, 2}), WithBoolInfo("retry", true)),
+			},
+			result: &result{severity: log.SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					_, callstack, _ := GetLoggingInfo(args.err)
+					result.body = "root error"
+					result.attributes = map[string]log.Value{
+						"exception.type":       log.StringValue("*errors.errorString"),
+						"exception.message":    log.StringValue("root error"),
+						"exception.stacktrace": log.StringValue(callstack),
+						"error.id":             log.StringValue(testErrorID),
+						"tenant":               log.StringValue("acme"),
+						"attempt":              log.Int64Value(2),
+						"shards":               log.SliceValue(log.Int64Value(1), log.Int64Value(2)),
+						"retry":                log.BoolValue(true),
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with named and unsupported infos",
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), WithStringSliceInfo("tags", testTags{"a", "b"}), testInfo{key: "channel", value: make(chan int)}),
+			},
+			result: &result{severity: log.SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					_, callstack, _ := GetLoggingInfo(args.err)
+					result.body = "root error"
+					result.attributes = map[string]log.Value{
+						"exception.type":       log.StringValue("*errors.errorString"),
+						"exception.message":    log.StringValue("root error"),
+						"exception.stacktrace": log.StringValue(callstack),
+						"error.id":             log.StringValue(testErrorID),
+						"tags":                 log.SliceValue(log.StringValue("a"), log.StringValue("b")),
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with uint infos either side of max int64",
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), WithUintInfo("max", uint64(math.MaxInt64)), WithUintInfo("overflow", uint64(math.MaxInt64)+1), WithUintSliceInfo("ids", []uint64{1, math.MaxUint64})),
+			},
+			result: &result{severity: log.SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					_, callstack, _ := GetLoggingInfo(args.err)
+					result.body = "root error"
+					result.attributes = map[string]log.Value{
+						"exception.type":       log.StringValue("*errors.errorString"),
+						"exception.message":    log.StringValue("root error"),
+						"exception.stacktrace": log.StringValue(callstack),
+						"error.id":             log.StringValue(testErrorID),
+						"max":                  log.Int64Value(math.MaxInt64),
+						"overflow":             log.StringValue("9223372036854775808"),
+						"ids":                  log.SliceValue(log.StringValue("1"), log.StringValue("18446744073709551615")),
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with standard error",
+			args: &args{
+				err: errors.New("standard error"),
+			},
+			result: &result{
+				severity: log.SeverityError,
+				body:     "standard error",
+				attributes: map[string]log.Value{
+					"exception.type":    log.StringValue("*errors.errorString"),
+					"exception.message": log.StringValue("standard error"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with critical severity",
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), SeverityKey.Info(SeverityCritical)),
+			},
+			result: &result{severity: log.SeverityFatal},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					_, callstack, _ := GetLoggingInfo(args.err)
+					result.body = "root error"
+					result.attributes = map[string]log.Value{
+						"exception.type":       log.StringValue("*errors.errorString"),
+						"exception.message":    log.StringValue("root error"),
+						"exception.stacktrace": log.StringValue(callstack),
+						"error.id":             log.StringValue(testErrorID),
+						"severity":             log.StringValue("critical"),
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			record := NewLogRecord(args.err)
+
+			// Assert
+			assert.Equal(t, result.severity, record.Severity())
+			assert.Equal(t, result.severity.String(), record.SeverityText())
+			assert.Equal(t, log.StringValue(result.body), record.Body())
+			assert.False(t, record.Timestamp().IsZero())
+
+			attributes := getLogAttributes(record)
+			assert.Len(t, attributes, len(result.attributes))
+			for key, value := range result.attributes {
+				assert.True(t, value.Equal(attributes[key]), key)
+			}
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestEmitError(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		ctx context.Context
+		err error
+	}
+	type result struct {
+		spanContext trace.SpanContext
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with the span of the innermost structured context",
+			args: &args{
+				ctx: trace.ContextWithSpanContext(context.Background(), newSpanContext(1, 1)),
+				err: New(&testContext{
+					Context: trace.ContextWithSpanContext(context.Background(), newSpanContext(2, 2)),
+				}, New(&testContext{
+					Context: trace.ContextWithSpanContext(context.Background(), newSpanContext(2, 3)),
+				}, New(nil, fmt.Errorf("root error")))),
+			},
+			result: &result{spanContext: newSpanContext(2, 3)},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with the span of ctx when no structured context carries one",
+			args: &args{
+				ctx: trace.ContextWithSpanContext(context.Background(), newSpanContext(1, 1)),
+				err: New(&testContext{
+					Context: context.Background(),
+				}, fmt.Errorf("root error")),
+			},
+			result: &result{spanContext: newSpanContext(1, 1)},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with standard error",
+			args: &args{
+				ctx: trace.ContextWithSpanContext(context.Background(), newSpanContext(1, 1)),
+				err: errors.New("standard error"),
+			},
+			result: &result{spanContext: newSpanContext(1, 1)},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			logger := &testLogger{}
+
+			// Act
+			actFunc(t)
+			EmitError(args.ctx, logger, args.err)
+
+			// Assert
+			assert.Len(t, logger.records, 1)
+			assert.Equal(t, result.spanContext, trace.SpanContextFromContext(logger.contexts[0]))
+
+			assertFunc(t)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package terror
+
+import (
+	"context"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	ErrorKindAttribute        = "error.kind"
+	ErrorFingerprintAttribute = "error.fingerprint"
+)
+
+// ErrorCounter counts errors in an otel metric by kind and fingerprint, along with the values of
+// allowed additional info keys. Only allow keys with a few distinct values, e.g. "operation", never
+// IDs, as each combination of attribute values is a separate time series. Expected errors are
+// counted in errors.expected rather than errors, so they're left out of error rates, see IsExpected.
+type ErrorCounter struct {
+	counter            metric.Int64Counter
+	expectedCounter    metric.Int64Counter
+	allowedKeys        []string
+	fingerprintOptions []FingerprintOption
+}
+
+func NewErrorCounter(meter metric.Meter, allowedKeys []string, fingerprintOptions ...FingerprintOption) (instance *ErrorCounter, err error) {
+	counter, err := meter.Int64Counter("errors",
+		metric.WithDescription("Errors recorded, by kind and fingerprint"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	expectedCounter, err := meter.Int64Counter("errors.expected",
+		metric.WithDescription("Expected errors recorded, by kind and fingerprint"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ErrorCounter{
+		counter:            counter,
+		expectedCounter:    expectedCounter,
+		allowedKeys:        slices.Clone(allowedKeys),
+		fingerprintOptions: fingerprintOptions,
+	}, nil
+}
+
+func (instance *ErrorCounter) Record(ctx context.Context, err error) {
+	counter := instance.counter
+	if IsExpected(err) {
+		counter = instance.expectedCounter
+	}
+
+	counter.Add(ctx, 1, metric.WithAttributes(instance.getAttributes(err)...))
+}
+
+func (instance *ErrorCounter) getAttributes(err error) (attributes []attribute.KeyValue) {
+	attributes = make([]attribute.KeyValue, 0, len(instance.allowedKeys)+2)
+	attributes = append(attributes, attribute.String(ErrorFingerprintAttribute, Fingerprint(err, instance.fingerprintOptions...)))
+
+	kind := GetKind(err)
+	if kind != "" {
+		attributes = append(attributes, attribute.String(ErrorKindAttribute, string(kind)))
+	}
+
+	_, _, additionalInfo := GetLoggingInfo(err)
+	for _, info := range additionalInfo {
+		if !slices.Contains(instance.allowedKeys, info.GetKey()) {
+			continue
+		}
+
+		keyValue, ok := ToAttribute(info)
+		if ok {
+			attributes = append(attributes, keyValue)
+		}
+	}
+
+	return attributes
+}
@@ -0,0 +1,158 @@
+package terror
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func distinctAttributes(attributes ...attribute.KeyValue) (distinct attribute.Distinct) {
+	set := attribute.NewSet(attributes...)
+
+	return set.Equivalent()
+}
+
+func TestErrorCounter(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		allowedKeys []string
+		errs        []error
+	}
+	type result struct {
+		metrics map[string]map[attribute.Distinct]int64
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "counts by kind and fingerprint, with allowed keys only",
+			args: &args{
+				allowedKeys: []string{"operation"},
+				errs: []error{
+					newFingerprintError("user 1 not found", KindKey.Info("not_found"), WithStringInfo("operation", "get_user"), WithStringInfo("user_id", "1")),
+					newFingerprintError("user 2 not found", KindKey.Info("not_found"), WithStringInfo("operation", "get_user"), WithStringInfo("user_id", "2")),
+					newFingerprintError("user 3 not found", KindKey.Info("not_found"), WithStringInfo("operation", "delete_user"), WithStringInfo("user_id", "3")),
+				},
+			},
+			result: &result{},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					fingerprint := Fingerprint(args.errs[0])
+					result.metrics = map[string]map[attribute.Distinct]int64{
+						"errors": {
+							distinctAttributes(
+								attribute.String(ErrorFingerprintAttribute, fingerprint),
+								attribute.String(ErrorKindAttribute, "not_found"),
+								attribute.String("operation", "get_user"),
+							): 2,
+							distinctAttributes(
+								attribute.String(ErrorFingerprintAttribute, fingerprint),
+								attribute.String(ErrorKindAttribute, "not_found"),
+								attribute.String("operation", "delete_user"),
+							): 1,
+						},
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "omits the kind when there is none",
+			args: &args{
+				allowedKeys: []string{},
+				errs: []error{
+					errors.New("standard error"),
+				},
+			},
+			result: &result{},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					result.metrics = map[string]map[attribute.Distinct]int64{
+						"errors": {
+							distinctAttributes(
+								attribute.String(ErrorFingerprintAttribute, Fingerprint(args.errs[0])),
+							): 1,
+						},
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "counts expected errors separately",
+			args: &args{
+				allowedKeys: []string{},
+				errs: []error{
+					newFingerprintError("user 1 not found", KindKey.Info(KindNotFound), ExpectedKey.Info(true)),
+				},
+			},
+			result: &result{},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					result.metrics = map[string]map[attribute.Distinct]int64{
+						"errors.expected": {
+							distinctAttributes(
+								attribute.String(ErrorFingerprintAttribute, Fingerprint(args.errs[0])),
+								attribute.String(ErrorKindAttribute, "not_found"),
+							): 1,
+						},
+					}
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			reader := sdkmetric.NewManualReader()
+			meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			instance, err := NewErrorCounter(meterProvider.Meter("test"), args.allowedKeys)
+			assert.NoError(t, err)
+
+			// Act
+			actFunc(t)
+			for _, err := range args.errs {
+				instance.Record(context.Background(), err)
+			}
+
+			// Assert
+			resourceMetrics := metricdata.ResourceMetrics{}
+			assert.NoError(t, reader.Collect(context.Background(), &resourceMetrics))
+			assert.Len(t, resourceMetrics.ScopeMetrics, 1)
+
+			metrics := make(map[string]map[attribute.Distinct]int64)
+			for _, metric := range resourceMetrics.ScopeMetrics[0].Metrics {
+				sum, ok := metric.Data.(metricdata.Sum[int64])
+				assert.True(t, ok)
+				dataPoints := make(map[attribute.Distinct]int64)
+				for _, dataPoint := range sum.DataPoints {
+					dataPoints[dataPoint.Attributes.Equivalent()] = dataPoint.Value
+				}
+				if len(dataPoints) > 0 {
+					metrics[metric.Name] = dataPoints
+				}
+			}
+			assert.Equal(t, result.metrics, metrics)
+
+			assertFunc(t)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package terror
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// w3cPropagator propagates the W3C traceparent, tracestate and baggage headers, regardless of the
+// global otel propagator
+var w3cPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// PropagatedContext is a StructuredContext for a request from another service, carrying the trace
+// context and baggage it sent, so spans started from it continue the caller's trace
+type PropagatedContext struct {
+	context.Context
+
+	additionalInfo AdditionalInfos
+}
+
+// GetAdditionalInfo returns a copy, so callers appending to it can't overwrite each other's info
+func (instance *PropagatedContext) GetAdditionalInfo() (additionalInfo AdditionalInfos) {
+	return slices.Clone(instance.additionalInfo)
+}
+
+type propagationOptions struct {
+	baggageInfo bool
+	baggageKeys []string
+}
+
+type PropagationOption func(options *propagationOptions)
+
+// WithBaggageInfo adds the baggage members as string additional info of errors created with the
+// context, only those with the given keys if any are given. Baggage is set by the caller, so only
+// include members that are safe to log.
+func WithBaggageInfo(keys ...string) (option PropagationOption) {
+	return func(options *propagationOptions) {
+		options.baggageInfo = true
+		options.baggageKeys = keys
+	}
+}
+
+// NewHTTPHeaderContext extracts the W3C trace context and baggage from the request headers
+func NewHTTPHeaderContext(ctx context.Context, header http.Header, options ...PropagationOption) (instance *PropagatedContext) {
+	return newPropagatedContext(ctx, propagation.HeaderCarrier(header), options)
+}
+
+// NewMetadataContext extracts the W3C trace context and baggage from gRPC style metadata, i.e. a
+// metadata.MD, whose keys are lowercase
+func NewMetadataContext(ctx context.Context, metadata map[string][]string, options ...PropagationOption) (instance *PropagatedContext) {
+	return newPropagatedContext(ctx, metadataCarrier(metadata), options)
+}
+
+// InjectHTTPHeader sets the W3C trace context and baggage of ctx on the outgoing request headers
+func InjectHTTPHeader(ctx context.Context, header http.Header) {
+	w3cPropagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// InjectMetadata sets the W3C trace context and baggage of ctx on gRPC style outgoing metadata
+func InjectMetadata(ctx context.Context, metadata map[string][]string) {
+	w3cPropagator.Inject(ctx, metadataCarrier(metadata))
+}
+
+func newPropagatedContext(ctx context.Context, carrier propagation.TextMapCarrier, options []PropagationOption) (instance *PropagatedContext) {
+	resolved := &propagationOptions{}
+	for _, option := range options {
+		option(resolved)
+	}
+
+	ctx = w3cPropagator.Extract(ctx, carrier)
+
+	additionalInfo := make(AdditionalInfos, 0)
+	if resolved.baggageInfo {
+		members := baggage.FromContext(ctx).Members()
+		// Members are unordered, sort them so the additional info is stable
+		slices.SortFunc(members, func(a, b baggage.Member) int {
+			return strings.Compare(a.Key(), b.Key())
+		})
+		for _, member := range members {
+			if len(resolved.baggageKeys) > 0 && !slices.Contains(resolved.baggageKeys, member.Key()) {
+				continue
+			}
+			additionalInfo = append(additionalInfo, WithStringInfo(member.Key(), member.Value()))
+		}
+	}
+
+	return &PropagatedContext{
+		Context:        ctx,
+		additionalInfo: additionalInfo,
+	}
+}
+
+// metadataCarrier adapts gRPC style metadata, keys are lowercased as gRPC requires
+type metadataCarrier map[string][]string
+
+func (instance metadataCarrier) Get(key string) (value string) {
+	values := instance[strings.ToLower(key)]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (instance metadataCarrier) Set(key string, value string) {
+	instance[strings.ToLower(key)] = []string{value}
+}
+
+func (instance metadataCarrier) Keys() (keys []string) {
+	keys = make([]string, 0, len(instance))
+	for key := range instance {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,296 @@
+package terror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	testTracestate  = "vendor=value"
+	testBaggage     = "tenant=acme,user=alice"
+)
+
+func TestNewHTTPHeaderContext(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		header  http.Header
+		options []PropagationOption
+	}
+	type result struct {
+		traceID        string
+		spanID         string
+		traceState     string
+		additionalInfo AdditionalInfos
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with trace context and baggage",
+			args: &args{
+				header: http.Header{
+					"Traceparent": {testTraceparent},
+					"Tracestate":  {testTracestate},
+					"Baggage":     {testBaggage},
+				},
+				options: []PropagationOption{},
+			},
+			result: &result{
+				traceID:        "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanID:         "00f067aa0ba902b7",
+				traceState:     testTracestate,
+				additionalInfo: AdditionalInfos{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with baggage info",
+			args: &args{
+				header: http.Header{
+					"Traceparent": {testTraceparent},
+					"Baggage":     {testBaggage},
+				},
+				options: []PropagationOption{WithBaggageInfo()},
+			},
+			result: &result{
+				traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanID:  "00f067aa0ba902b7",
+				additionalInfo: AdditionalInfos{
+					WithStringInfo("tenant", "acme"),
+					WithStringInfo("user", "alice"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with baggage info for given keys",
+			args: &args{
+				header: http.Header{
+					"Traceparent": {testTraceparent},
+					"Baggage":     {testBaggage},
+				},
+				options: []PropagationOption{WithBaggageInfo("tenant")},
+			},
+			result: &result{
+				traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanID:  "00f067aa0ba902b7",
+				additionalInfo: AdditionalInfos{
+					WithStringInfo("tenant", "acme"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "without headers",
+			args: &args{
+				header:  http.Header{},
+				options: []PropagationOption{WithBaggageInfo()},
+			},
+			result: &result{
+				traceID:        "00000000000000000000000000000000",
+				spanID:         "0000000000000000",
+				additionalInfo: AdditionalInfos{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			instance := NewHTTPHeaderContext(context.Background(), args.header, args.options...)
+
+			// Assert
+			spanContext := trace.SpanContextFromContext(instance)
+			assert.Equal(t, result.traceID, spanContext.TraceID().String())
+			assert.Equal(t, result.spanID, spanContext.SpanID().String())
+			assert.Equal(t, result.traceState, spanContext.TraceState().String())
+			assert.Equal(t, result.additionalInfo, instance.GetAdditionalInfo())
+
+			_, _, additionalInfo := GetLoggingInfo(New(instance, fmt.Errorf("root error")))
+			assert.Equal(t, result.additionalInfo, additionalInfo)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestNewMetadataContext(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		metadata map[string][]string
+		options  []PropagationOption
+	}
+	type result struct {
+		traceID        string
+		spanID         string
+		additionalInfo AdditionalInfos
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with trace context and baggage",
+			args: &args{
+				metadata: map[string][]string{
+					"traceparent": {testTraceparent},
+					"baggage":     {testBaggage},
+				},
+				options: []PropagationOption{WithBaggageInfo("user")},
+			},
+			result: &result{
+				traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanID:  "00f067aa0ba902b7",
+				additionalInfo: AdditionalInfos{
+					WithStringInfo("user", "alice"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			instance := NewMetadataContext(context.Background(), args.metadata, args.options...)
+
+			// Assert
+			spanContext := trace.SpanContextFromContext(instance)
+			assert.Equal(t, result.traceID, spanContext.TraceID().String())
+			assert.Equal(t, result.spanID, spanContext.SpanID().String())
+			assert.True(t, spanContext.IsRemote())
+			assert.Equal(t, result.additionalInfo, instance.GetAdditionalInfo())
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		ctx context.Context
+	}
+	type result struct {
+		header   http.Header
+		metadata map[string][]string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			// A single baggage member, as the order of members isn't kept
+			name: "with propagated context",
+			args: &args{},
+			result: &result{
+				header: http.Header{
+					"Traceparent": {testTraceparent},
+					"Tracestate":  {testTracestate},
+					"Baggage":     {"tenant=acme"},
+				},
+				metadata: map[string][]string{
+					"traceparent": {testTraceparent},
+					"tracestate":  {testTracestate},
+					"baggage":     {"tenant=acme"},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					args.ctx = NewHTTPHeaderContext(context.Background(), result.header.Clone())
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "without trace context",
+			args: &args{ctx: context.Background()},
+			result: &result{
+				header:   http.Header{},
+				metadata: map[string][]string{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			header := http.Header{}
+			metadata := map[string][]string{}
+
+			// Act
+			actFunc(t)
+			InjectHTTPHeader(args.ctx, header)
+			InjectMetadata(args.ctx, metadata)
+
+			// Assert
+			assert.Equal(t, result.header, header)
+			assert.Equal(t, result.metadata, metadata)
+
+			assertFunc(t)
+		})
+	}
+}
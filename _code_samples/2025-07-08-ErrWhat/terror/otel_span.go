@@ -0,0 +1,31 @@
+package terror
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SetSpanStatus sets the status of the span from the error it ended with. An expected error, see
+// IsExpected, is OK with an "expected error" event, so it doesn't count as a failed span, otherwise
+// the status follows the error's Severity.
+func SetSpanStatus(span trace.Span, err error) {
+	switch {
+	case err == nil:
+		span.SetStatus(codes.Ok, "")
+	case IsExpected(err):
+		attributes := []attribute.KeyValue{
+			attribute.String("exception.type", getExceptionType(err)),
+			attribute.String("exception.message", err.Error()),
+		}
+		kind := GetKind(err)
+		if kind != "" {
+			attributes = append(attributes, attribute.String(ErrorKindAttribute, string(kind)))
+		}
+
+		span.AddEvent("expected error", trace.WithAttributes(attributes...))
+		span.SetStatus(codes.Ok, "")
+	default:
+		span.SetStatus(GetSeverity(err).SpanStatus(), err.Error())
+	}
+}
@@ -0,0 +1,130 @@
+package terror
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type testSpan struct {
+	noop.Span
+
+	status      codes.Code
+	description string
+	events      map[string][]attribute.KeyValue
+}
+
+func (instance *testSpan) SetStatus(code codes.Code, description string) {
+	instance.status = code
+	instance.description = description
+}
+
+func (instance *testSpan) AddEvent(name string, options ...trace.EventOption) {
+	config := trace.NewEventConfig(options...)
+	instance.events[name] = config.Attributes()
+}
+
+func TestSetSpanStatus(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+	}
+	type result struct {
+		status      codes.Code
+		description string
+		events      map[string][]attribute.KeyValue
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with no error",
+			args: &args{err: nil},
+			result: &result{
+				status: codes.Ok,
+				events: map[string][]attribute.KeyValue{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with error",
+			args: &args{err: New(nil, fmt.Errorf("connection reset"))},
+			result: &result{
+				status:      codes.Error,
+				description: "connection reset",
+				events:      map[string][]attribute.KeyValue{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with warning",
+			args: &args{err: New(nil, fmt.Errorf("retrying"), SeverityKey.Info(SeverityWarning))},
+			result: &result{
+				status:      codes.Unset,
+				description: "retrying",
+				events:      map[string][]attribute.KeyValue{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with expected error",
+			args: &args{err: New(nil, fmt.Errorf("user not found"), KindKey.Info(KindNotFound), ExpectedKey.Info(true))},
+			result: &result{
+				status: codes.Ok,
+				events: map[string][]attribute.KeyValue{
+					"expected error": {
+						attribute.String("exception.type", "*errors.errorString"),
+						attribute.String("exception.message", "user not found"),
+						attribute.String(ErrorKindAttribute, "not_found"),
+					},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			span := &testSpan{events: make(map[string][]attribute.KeyValue)}
+
+			// Act
+			actFunc(t)
+			SetSpanStatus(span, args.err)
+
+			// Assert
+			assert.Equal(t, result.status, span.status)
+			assert.Equal(t, result.description, span.description)
+			assert.Equal(t, result.events, span.events)
+
+			assertFunc(t)
+		})
+	}
+}
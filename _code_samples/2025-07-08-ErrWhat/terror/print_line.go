@@ -0,0 +1,179 @@
+package terror
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type printOptions struct {
+	maxFrames  int
+	color      *bool
+	moduleRoot *string
+}
+
+type PrintOption func(options *printOptions)
+
+// WithMaxFrames shortens the callstack to its top count frames, 0 or less keeps every frame
+func WithMaxFrames(count int) (option PrintOption) {
+	return func(options *printOptions) {
+		options.maxFrames = count
+	}
+}
+
+func getPrintOptions(options []PrintOption) (resolved *printOptions) {
+	resolved = &printOptions{}
+	for _, option := range options {
+		option(resolved)
+	}
+
+	return resolved
+}
+
+// getPrintInfo is GetLoggingInfo, split into frames and with the additional info keys sorted
+func getPrintInfo(err error, options *printOptions) (cause string, frames []string, keys []string, additionalInfo map[string]any) {
+	cause, callstack, infos := GetLoggingInfo(err)
+
+	frames = strings.Split(callstack, "\n")
+	if options.maxFrames > 0 && len(frames) > options.maxFrames {
+		frames = frames[:options.maxFrames]
+	}
+
+	additionalInfo = infos.ToJSON()
+
+	return cause, frames, sortedKeys(additionalInfo), additionalInfo
+}
+
+func sortedKeys(additionalInfo map[string]any) (keys []string) {
+	keys = make([]string, 0, len(additionalInfo))
+	for key := range additionalInfo {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// PrintErrorLogfmt renders the error as a single logfmt line, with the cause and ID first, then the
+// additional info in key order, then the callstack. Info keys clashing with those fields are
+// prefixed with info., so a parser keeping the last value can't lose the cause or callstack, e.g.
+//
+//	cause="root error" id=01K0B0Q1S2T3V4W5X6Y7Z8 key1=value1 callstack="/path/file.go: 12\n/path/main.go: 8"
+func PrintErrorLogfmt(err error, options ...PrintOption) (errString string) {
+	cause, frames, keys, additionalInfo := getPrintInfo(err, getPrintOptions(options))
+
+	builder := strings.Builder{}
+	builder.WriteString("cause=")
+	builder.WriteString(quoteLogfmtValue(cause))
+	id, found := GetErrorID(err)
+	if found {
+		builder.WriteString(" id=")
+		builder.WriteString(quoteLogfmtValue(id))
+	}
+	for _, key := range keys {
+		builder.WriteByte(' ')
+		builder.WriteString(formatInfoKey(key))
+		builder.WriteByte('=')
+		builder.WriteString(quoteLogfmtValue(fmt.Sprintf("%v", additionalInfo[key])))
+	}
+	builder.WriteString(" callstack=")
+	builder.WriteString(quoteLogfmtValue(strings.Join(frames, "\n")))
+
+	return builder.String()
+}
+
+// PrintErrorLine renders the same sections as PrintError on a single line, e.g.
+//
+//	Cause: root error | ID: 01K0B0Q1S2T3V4W5X6Y7Z8 | Additional Info: key1=value1 | Callstack: /path/file.go: 12; /path/main.go: 8
+func PrintErrorLine(err error, options ...PrintOption) (errString string) {
+	cause, frames, keys, additionalInfo := getPrintInfo(err, getPrintOptions(options))
+
+	builder := strings.Builder{}
+	builder.WriteString("Cause: ")
+	builder.WriteString(escapeLine(cause))
+	id, found := GetErrorID(err)
+	if found {
+		builder.WriteString(" | ID: ")
+		builder.WriteString(id)
+	}
+	if len(keys) > 0 {
+		builder.WriteString(" | Additional Info:")
+		for _, key := range keys {
+			builder.WriteByte(' ')
+			builder.WriteString(formatInfoKey(key))
+			builder.WriteByte('=')
+			builder.WriteString(quoteLogfmtValue(fmt.Sprintf("%v", additionalInfo[key])))
+		}
+	}
+	builder.WriteString(" | Callstack: ")
+	for i, frame := range frames {
+		if i > 0 {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(escapeLine(frame))
+	}
+
+	return builder.String()
+}
+
+// quoteLogfmtValue quotes the value when it would otherwise break the key=value pairs, i.e. it's
+// empty or contains spaces, quotes, equals or control characters such as newlines
+func quoteLogfmtValue(value string) (quoted string) {
+	if value == "" {
+		return `""`
+	}
+
+	needsQuoting := strings.ContainsFunc(value, func(r rune) bool {
+		return r == '"' || r == '=' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+	})
+	if needsQuoting {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// formatLogfmtKey replaces any characters which can't appear in a logfmt key
+func formatLogfmtKey(key string) (formatted string) {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r == '"' || r == '=' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+// reservedKeys are the fields PrintErrorLogfmt writes itself
+var reservedKeys = []string{"cause", "id", "callstack"}
+
+// formatInfoKey is formatLogfmtKey, with keys clashing with the reserved fields prefixed with info.
+func formatInfoKey(key string) (formatted string) {
+	formatted = formatLogfmtKey(key)
+	if slices.Contains(reservedKeys, formatted) {
+		return "info." + formatted
+	}
+
+	return formatted
+}
+
+// escapeLine escapes control characters, so the text can't span multiple lines
+func escapeLine(text string) (escaped string) {
+	builder := strings.Builder{}
+	for _, r := range text {
+		if unicode.IsControl(r) {
+			quoted := strconv.QuoteRune(r)
+			builder.WriteString(quoted[1 : len(quoted)-1])
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,334 @@
+package terror
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintErrorLogfmt(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		options []PrintOption
+	}
+	type result struct {
+		errString string
+		frames    int
+	}
+	type testConfig struct {
+		name          string
+		instance      error
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "formats a structured error with sorted keys and quoted values",
+			instance: New(nil, fmt.Errorf("root error"),
+				WithStringInfo("key2", "has \"quotes\"\nand newlines"),
+				WithStringInfo("key1", "value1"),
+				WithStringInfo("key3", ""),
+				WithStringSliceInfo("key4", []string{"one", "two"}),
+			),
+			args: &args{
+				options: []PrintOption{WithMaxFrames(1)},
+			},
+			result: &result{
+				errString: `cause="root error" id=` + testErrorID + ` key1=value1 key2="has \"quotes\"\nand newlines" key3="" key4="[one two]" callstack="`,
+				frames:    1,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					// Find the current file path
+					_, currentFilePath, _, ok := runtime.Caller(1)
+					assert.True(t, ok)
+
+					result.errString += currentFilePath
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "keeps every frame by default",
+			instance: New(nil, fmt.Errorf("root error")),
+			args: &args{
+				options: []PrintOption{},
+			},
+			result: &result{
+				errString: `cause="root error" id=` + testErrorID + ` callstack="`,
+				frames:    3,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "replaces characters which can't appear in a key",
+			instance: New(nil, fmt.Errorf("root error"), WithStringInfo("user id=", "value1")),
+			args: &args{
+				options: []PrintOption{WithMaxFrames(1)},
+			},
+			result: &result{
+				errString: `cause="root error" id=` + testErrorID + ` user_id_=value1 callstack="`,
+				frames:    1,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "prefixes keys clashing with the fields",
+			instance: New(nil, fmt.Errorf("root error"), WithStringInfo("cause", "user"), WithStringInfo("id", "1"), WithStringInfo("callstack", "z")),
+			args: &args{
+				options: []PrintOption{WithMaxFrames(1)},
+			},
+			result: &result{
+				errString: `cause="root error" id=` + testErrorID + ` info.callstack=z info.cause=user info.id=1 callstack="`,
+				frames:    1,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "formats a standard go error",
+			instance: fmt.Errorf("root error"),
+			args: &args{
+				options: []PrintOption{},
+			},
+			result: &result{
+				errString: `cause="root error" callstack="unwrapped error - no callstack"`,
+				frames:    1,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			errString := PrintErrorLogfmt(instance, args.options...)
+
+			// Assert
+			assert.Contains(t, errString, result.errString)
+			assert.NotContains(t, errString, "\n")
+			assert.Equal(t, result.frames, strings.Count(errString, `\n/`)+1)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestPrintErrorLine(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		options []PrintOption
+	}
+	type result struct {
+		errString string
+	}
+	type testConfig struct {
+		name          string
+		instance      error
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "formats a structured error on a single line",
+			instance: New(nil, fmt.Errorf("root\terror\non two lines"),
+				WithStringInfo("key2", "has space"),
+				WithIntInfo("key1", 1),
+			),
+			args: &args{
+				options: []PrintOption{WithMaxFrames(2)},
+			},
+			result: &result{
+				errString: `Cause: root\terror\non two lines | ID: ` + testErrorID + ` | Additional Info: key1=1 key2="has space" | Callstack: `,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					// Find the current file path
+					_, currentFilePath, _, ok := runtime.Caller(1)
+					assert.True(t, ok)
+
+					result.errString += currentFilePath
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "omits the additional info section when there is none",
+			instance: New(nil, fmt.Errorf("root error")),
+			args: &args{
+				options: []PrintOption{WithMaxFrames(1)},
+			},
+			result: &result{
+				errString: `Cause: root error | ID: ` + testErrorID + ` | Callstack: `,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "prefixes keys clashing with the fields",
+			instance: New(nil, fmt.Errorf("root error"), WithStringInfo("cause", "user"), WithStringInfo("callstack", "z")),
+			args: &args{
+				options: []PrintOption{WithMaxFrames(1)},
+			},
+			result: &result{
+				errString: `Cause: root error | ID: ` + testErrorID + ` | Additional Info: info.callstack=z info.cause=user | Callstack: `,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "formats a standard go error",
+			instance: fmt.Errorf("root error"),
+			args: &args{
+				options: []PrintOption{},
+			},
+			result: &result{
+				errString: `Cause: root error | Callstack: unwrapped error - no callstack`,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			errString := PrintErrorLine(instance, args.options...)
+
+			// Assert
+			assert.Contains(t, errString, result.errString)
+			assert.NotContains(t, errString, "\n")
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestQuoteLogfmtValue(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		value string
+	}
+	type result struct {
+		quoted string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "leaves a simple value unquoted",
+			args:   &args{value: "value1"},
+			result: &result{quoted: `value1`},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "quotes an empty value",
+			args:   &args{value: ""},
+			result: &result{quoted: `""`},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "quotes a value with spaces",
+			args:   &args{value: "value 1"},
+			result: &result{quoted: `"value 1"`},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "quotes and escapes a value with quotes",
+			args:   &args{value: `say "hi"`},
+			result: &result{quoted: `"say \"hi\""`},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "quotes and escapes a value with newlines",
+			args:   &args{value: "line1\nline2"},
+			result: &result{quoted: `"line1\nline2"`},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "quotes a value with equals",
+			args:   &args{value: "a=b"},
+			result: &result{quoted: `"a=b"`},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			quoted := quoteLogfmtValue(args.value)
+
+			// Assert
+			assert.Equal(t, result.quoted, quoted)
+
+			assertFunc(t)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package terror
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// WithColor forces colors on or off, rather than detecting whether the output is a terminal
+func WithColor(enabled bool) (option PrintOption) {
+	return func(options *printOptions) {
+		options.color = &enabled
+	}
+}
+
+// WithModuleRoot sets the directory frame paths are shortened relative to, by default it's the
+// nearest directory containing a go.mod, from the working directory upwards
+func WithModuleRoot(moduleRoot string) (option PrintOption) {
+	return func(options *printOptions) {
+		options.moduleRoot = &moduleRoot
+	}
+}
+
+var getModuleRoot = sync.OnceValue(func() (moduleRoot string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		_, err = os.Stat(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+})
+
+// FprintErrorPretty writes PrintErrorPretty to the writer, with colors when the writer is a
+// terminal and the NO_COLOR environment variable is not set, unless WithColor is given
+func FprintErrorPretty(writer io.Writer, err error, options ...PrintOption) (writeErr error) {
+	options = append([]PrintOption{WithColor(isColorTerminal(writer, os.Getenv("NO_COLOR")))}, options...)
+	_, writeErr = io.WriteString(writer, PrintErrorPretty(err, options...))
+
+	return writeErr
+}
+
+func isColorTerminal(writer io.Writer, noColor string) (isColor bool) {
+	// https://no-color.org/
+	if noColor != "" {
+		return false
+	}
+
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// PrintErrorPretty is PrintError for local development, the cause is highlighted, additional info
+// is aligned, paths are shortened relative to the module root, and frames from the standard library
+// and module cache are dimmed. Colors are only used with WithColor, see FprintErrorPretty.
+func PrintErrorPretty(err error, options ...PrintOption) (errString string) {
+	resolved := getPrintOptions(options)
+	color := resolved.color != nil && *resolved.color
+	moduleRoot := getModuleRoot()
+	if resolved.moduleRoot != nil {
+		moduleRoot = *resolved.moduleRoot
+	}
+
+	paint := func(text string, codes ...string) (painted string) {
+		if !color {
+			return text
+		}
+
+		return strings.Join(codes, "") + text + ansiReset
+	}
+
+	structuredError, ok := err.(*StructuredError)
+	if !ok {
+		return fmt.Sprintf("Unknown error: %s\n", paint(err.Error(), ansiBold, ansiRed))
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("Cause: %s\n", paint(structuredError.Error(), ansiBold, ansiRed)))
+	if structuredError.id != "" {
+		builder.WriteString(fmt.Sprintf("ID: %s\n", structuredError.id))
+	}
+
+	origin := formatOrigin(structuredError)
+	if origin != "" {
+		builder.WriteString(fmt.Sprintf("Created: %s\n", origin))
+	}
+
+	additionalInfo := structuredError.flattenAdditionalInfo().ToJSON()
+	keys := sortedKeys(additionalInfo)
+	if len(keys) > 0 {
+		keyWidth := 0
+		for _, key := range keys {
+			keyWidth = max(keyWidth, len(key))
+		}
+
+		builder.WriteString("Additional Info:\n")
+		for _, key := range keys {
+			padding := strings.Repeat(" ", keyWidth-len(key))
+			builder.WriteString(fmt.Sprintf("\t%s:%s %v\n", paint(key, ansiCyan), padding, additionalInfo[key]))
+		}
+	}
+
+	builder.WriteString("Callstack:\n")
+	callstack := getFrames(structuredError.getOriginCallstack())
+	if len(callstack) == 0 {
+		builder.WriteString(fmt.Sprintf("\t%s\n", paint("unwrapped error - no callstack", ansiDim)))
+	}
+	if resolved.maxFrames > 0 && len(callstack) > resolved.maxFrames {
+		callstack = callstack[:resolved.maxFrames]
+	}
+	for _, frame := range callstack {
+		line := formatFrame(shortenFrame(frame, moduleRoot))
+		if isLibraryFrame(frame, moduleRoot) {
+			line = paint(line, ansiDim)
+		}
+		builder.WriteString(fmt.Sprintf("\t%s\n", line))
+	}
+
+	return builder.String()
+}
+
+func shortenFrame(frame runtime.Frame, moduleRoot string) (shortened runtime.Frame) {
+	if moduleRoot == "" {
+		return frame
+	}
+
+	relative, err := filepath.Rel(moduleRoot, frame.File)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return frame
+	}
+
+	frame.File = relative
+
+	return frame
+}
+
+// isLibraryFrame reports whether the frame is from the standard library or the module cache, i.e.
+// code the developer is unlikely to be debugging
+func isLibraryFrame(frame runtime.Frame, moduleRoot string) (isLibrary bool) {
+	file := filepath.ToSlash(frame.File)
+	if strings.Contains(file, "/pkg/mod/") {
+		return true
+	}
+
+	if moduleRoot != "" && strings.HasPrefix(file, filepath.ToSlash(moduleRoot)+"/") {
+		return false
+	}
+
+	// Standard library packages have no dot in the first element of their import path
+	function := frame.Function
+	if strings.HasPrefix(function, "main.") {
+		return false
+	}
+	firstElement, _, found := strings.Cut(function, "/")
+	if !found {
+		firstElement, _, _ = strings.Cut(function, ".")
+	}
+
+	return function != "" && !strings.Contains(firstElement, ".")
+}
@@ -0,0 +1,314 @@
+package terror
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintErrorPretty(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		options []PrintOption
+	}
+	type result struct {
+		contains    []string
+		notContains []string
+	}
+	type testConfig struct {
+		name          string
+		instance      error
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "aligns additional info and shortens paths relative to the module root",
+			instance: New(nil, fmt.Errorf("root error"), WithStringInfo("key1", "value1"), WithStringInfo("longer_key", "value2")),
+			args: &args{
+				options: []PrintOption{WithColor(false)},
+			},
+			result: &result{
+				contains: []string{
+					"Cause: root error\nID: " + testErrorID + "\nAdditional Info:\n\tkey1:       value1\n\tlonger_key: value2\nCallstack:\n\tprint_pretty_test.go: ",
+				},
+				notContains: []string{"\x1b["},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					workingDirectory, err := os.Getwd()
+					assert.NoError(t, err)
+
+					args.options = append(args.options, WithModuleRoot(workingDirectory))
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "highlights the cause and dims standard library frames",
+			instance: New(nil, fmt.Errorf("root error"), WithStringInfo("key1", "value1")),
+			args: &args{
+				options: []PrintOption{WithColor(true), WithModuleRoot("")},
+			},
+			result: &result{
+				contains: []string{
+					"Cause: \x1b[1m\x1b[31mroot error\x1b[0m\n",
+					"\t\x1b[36mkey1\x1b[0m: value1\n",
+					"\t\x1b[2m",
+				},
+				notContains: []string{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					// Find the current file path, which isn't dimmed, whereas the testing package is
+					_, currentFilePath, _, ok := runtime.Caller(1)
+					assert.True(t, ok)
+
+					result.contains = append(result.contains, "Callstack:\n\t"+currentFilePath)
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "shortens the callstack to its top frames",
+			instance: New(nil, fmt.Errorf("root error")),
+			args: &args{
+				options: []PrintOption{WithColor(false), WithMaxFrames(1)},
+			},
+			result: &result{
+				contains:    []string{"Cause: root error\nID: " + testErrorID + "\nCallstack:\n\t"},
+				notContains: []string{"testing.go"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "notes an enriched standard go error has no callstack",
+			instance: newEnrichedLayer(fmt.Errorf("root error"), AdditionalInfos{WithStringInfo("key1", "value1")}),
+			args: &args{
+				options: []PrintOption{WithColor(false)},
+			},
+			result: &result{
+				contains:    []string{"Cause: root error\nAdditional Info:\n\tkey1: value1\nCallstack:\n\tunwrapped error - no callstack\n"},
+				notContains: []string{"ID: "},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "formats a standard go error",
+			instance: fmt.Errorf("root error"),
+			args: &args{
+				options: []PrintOption{WithColor(false)},
+			},
+			result: &result{
+				contains:    []string{"Unknown error: root error\n"},
+				notContains: []string{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			errString := PrintErrorPretty(instance, args.options...)
+
+			// Assert
+			for _, contains := range result.contains {
+				assert.Contains(t, errString, contains)
+			}
+			for _, notContains := range result.notContains {
+				assert.NotContains(t, errString, notContains)
+			}
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestIsColorTerminal(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		noColor string
+	}
+	type result struct {
+		isColor bool
+	}
+	type testConfig struct {
+		name          string
+		instance      io.Writer
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "isn't a terminal when writing to a buffer",
+			instance: &bytes.Buffer{},
+			args:     &args{noColor: ""},
+			result:   &result{isColor: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "isn't a terminal when writing to a file",
+			instance: nil,
+			args:     &args{noColor: ""},
+			result:   &result{isColor: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "honours NO_COLOR",
+			instance: os.Stdout,
+			args:     &args{noColor: "1"},
+			result:   &result{isColor: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+			if instance == nil {
+				file, err := os.CreateTemp(t.TempDir(), "output")
+				assert.NoError(t, err)
+				defer file.Close()
+
+				instance = file
+			}
+
+			// Act
+			actFunc(t)
+			isColor := isColorTerminal(instance, args.noColor)
+
+			// Assert
+			assert.Equal(t, result.isColor, isColor)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestIsLibraryFrame(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		frame      runtime.Frame
+		moduleRoot string
+	}
+	type result struct {
+		isLibrary bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "standard library frames are library frames",
+			args: &args{
+				frame:      runtime.Frame{Function: "net/http.(*conn).serve", File: "/usr/local/go/src/net/http/server.go"},
+				moduleRoot: "/src/service",
+			},
+			result: &result{isLibrary: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "module cache frames are library frames",
+			args: &args{
+				frame:      runtime.Frame{Function: "github.com/stretchr/testify/assert.Equal", File: "/home/user/go/pkg/mod/github.com/stretchr/testify@v1.10.0/assert/assertions.go"},
+				moduleRoot: "/src/service",
+			},
+			result: &result{isLibrary: true},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "module frames aren't library frames",
+			args: &args{
+				frame:      runtime.Frame{Function: "service/internal/users.Register", File: "/src/service/internal/users/register.go"},
+				moduleRoot: "/src/service",
+			},
+			result: &result{isLibrary: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "main package frames aren't library frames",
+			args: &args{
+				frame:      runtime.Frame{Function: "main.main", File: "/build/main.go"},
+				moduleRoot: "",
+			},
+			result: &result{isLibrary: false},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			isLibrary := isLibraryFrame(args.frame, args.moduleRoot)
+
+			// Assert
+			assert.Equal(t, result.isLibrary, isLibrary)
+
+			assertFunc(t)
+		})
+	}
+}
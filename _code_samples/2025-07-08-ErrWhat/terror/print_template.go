@@ -0,0 +1,193 @@
+package terror
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData is the data model error templates are executed with
+type TemplateData struct {
+	// Cause is the error message
+	Cause string
+	// ID is the ID the error originated with, empty when it isn't a StructuredError
+	ID string
+	// Origin is when and on which goroutine the error originated, as PrintError's Created line,
+	// empty unless either was recorded, see LayerInfo
+	Origin string
+	// Kind is the error's Kind, empty when none was set
+	Kind Kind
+	// Layers are the StructuredErrors in the chain, from the outermost inwards
+	Layers []TemplateLayer
+	// Infos is the flattened additional info of the chain, sorted by key as PrintError does
+	Infos []TemplateInfo
+	// Frames is the callstack of where the error originated, empty for errors other than StructuredError
+	Frames []TemplateFrame
+}
+
+type TemplateLayer struct {
+	// ContextInfos is the additional info of the layer's context, empty if it was already
+	// included by an outer layer sharing the context
+	ContextInfos []TemplateInfo
+	// Infos is the additional info given to New for the layer
+	Infos []TemplateInfo
+	// Time and GoroutineID are where the layer was created, if recorded, see LayerInfo
+	Time        time.Time
+	GoroutineID uint64
+}
+
+type TemplateInfo struct {
+	Key   string
+	Value any
+}
+
+type TemplateFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// TextTemplate renders the sections of PrintError for a StructuredError, with the kind when there is
+// one. Empty sections are left out, where PrintError notes an error has no callstack.
+var TextTemplate = template.Must(NewErrorTemplate("text", `Cause: {{.Cause}}
+{{if .ID}}ID: {{.ID}}
+{{end}}{{if .Origin}}Created: {{.Origin}}
+{{end}}{{if .Kind}}Kind: {{.Kind}}
+{{end}}{{if .Infos}}Additional Info:
+{{range .Infos}}	{{.Key}}: {{.Value}}
+{{end}}{{end}}{{if .Frames}}Callstack:
+{{range .Frames}}	{{.File}}: {{.Line}}
+{{end}}{{end}}`))
+
+// MarkdownTemplate renders the error for issue trackers
+var MarkdownTemplate = template.Must(NewErrorTemplate("markdown", `## {{escapeMarkdown .Cause}}
+{{if .ID}}
+**ID:** {{codeMarkdown .ID}}
+{{end}}{{if .Origin}}
+**Created:** {{escapeMarkdown .Origin}}
+{{end}}{{if .Kind}}
+**Kind:** {{codeMarkdown (print .Kind)}}
+{{end}}{{if .Infos}}
+### Additional Info
+
+| Key | Value |
+| --- | --- |
+{{range .Infos}}| {{codeMarkdown .Key}} | {{escapeMarkdown (print .Value)}} |
+{{end}}{{end}}{{if .Frames}}
+### Callstack
+
+`+"```text"+`
+{{range .Frames}}{{.Function}}
+	{{.File}}: {{.Line}}
+{{end}}`+"```"+`
+{{end}}`))
+
+// NewErrorTemplate parses a template with the functions the default templates use, escapeMarkdown
+// and codeMarkdown, which format text for Markdown
+func NewErrorTemplate(name string, text string) (instance *template.Template, err error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"escapeMarkdown": escapeMarkdown,
+		"codeMarkdown":   codeMarkdown,
+	}).Parse(text)
+}
+
+// PrintErrorTemplate renders the error with the template, see TemplateData for the data model
+func PrintErrorTemplate(err error, instance *template.Template) (errString string, templateErr error) {
+	builder := strings.Builder{}
+	templateErr = instance.Execute(&builder, NewTemplateData(err))
+	if templateErr != nil {
+		return "", templateErr
+	}
+
+	return builder.String(), nil
+}
+
+func NewTemplateData(err error) (data *TemplateData) {
+	data = &TemplateData{
+		Cause:  err.Error(),
+		Kind:   GetKind(err),
+		Layers: make([]TemplateLayer, 0),
+		Infos:  make([]TemplateInfo, 0),
+		Frames: make([]TemplateFrame, 0),
+	}
+
+	structuredError, ok := err.(*StructuredError)
+	if !ok {
+		return data
+	}
+	data.ID = structuredError.id
+	data.Origin = formatOrigin(structuredError)
+
+	visitedContexts := make(map[StructuredContext]bool)
+	current := structuredError
+	for current != nil {
+		layer := TemplateLayer{
+			ContextInfos: make([]TemplateInfo, 0),
+			Infos:        toTemplateInfos(current.additionalInfo),
+			Time:         current.layerInfo.Time,
+			GoroutineID:  current.layerInfo.GoroutineID,
+		}
+		if current.context != nil && !visitedContexts[current.context] {
+			layer.ContextInfos = toTemplateInfos(current.context.GetAdditionalInfo())
+			visitedContexts[current.context] = true
+		}
+		data.Layers = append(data.Layers, layer)
+
+		current, _ = current.cause.(*StructuredError)
+	}
+
+	additionalInfo := structuredError.flattenAdditionalInfo().ToJSON()
+	for _, key := range sortedKeys(additionalInfo) {
+		data.Infos = append(data.Infos, TemplateInfo{Key: key, Value: additionalInfo[key]})
+	}
+
+	for _, frame := range getFrames(structuredError.getOriginCallstack()) {
+		data.Frames = append(data.Frames, TemplateFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+	}
+
+	return data
+}
+
+func toTemplateInfos(infos AdditionalInfos) (templateInfos []TemplateInfo) {
+	templateInfos = make([]TemplateInfo, len(infos))
+	for i, info := range infos {
+		templateInfos[i] = TemplateInfo{Key: info.GetKey(), Value: info.GetValue()}
+	}
+
+	return templateInfos
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`, "\r\n", "<br>", "\n", "<br>",
+)
+
+// escapeMarkdown escapes text so it renders literally, including inside table cells
+func escapeMarkdown(text string) (escaped string) {
+	return markdownEscaper.Replace(text)
+}
+
+// codeMarkdown wraps text in a code span, with a fence longer than any run of backticks in it
+func codeMarkdown(text string) (code string) {
+	longestRun, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longestRun = max(longestRun, run)
+		} else {
+			run = 0
+		}
+	}
+
+	fence := strings.Repeat("`", longestRun+1)
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+
+	return fence + text + fence
+}
@@ -0,0 +1,289 @@
+package terror
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintErrorTemplate(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		template *template.Template
+	}
+	type result struct {
+		errString string
+		hasError  bool
+	}
+	type testConfig struct {
+		name          string
+		instance      error
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "renders the text template",
+			instance: New(nil, fmt.Errorf("root error"), KindKey.Info("not_found"), WithStringInfo("key1", "value1")),
+			args: &args{
+				template: TextTemplate,
+			},
+			result: &result{
+				errString: "Cause: root error\nID: " + testErrorID + "\nKind: not_found\nAdditional Info:\n\tkey1: value1\n\tkind: not_found\nCallstack:\n\t",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					// Find the current file path
+					_, currentFilePath, _, ok := runtime.Caller(1)
+					assert.True(t, ok)
+
+					result.errString += currentFilePath
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "renders the markdown template, escaping text",
+			instance: New(nil, fmt.Errorf("root | error"), WithStringInfo("key_1", "value|1")),
+			args: &args{
+				template: MarkdownTemplate,
+			},
+			result: &result{
+				errString: "## root \\| error\n\n**ID:** `" + testErrorID + "`\n\n### Additional Info\n\n| Key | Value |\n| --- | --- |\n| `key_1` | value\\|1 |\n\n### Callstack\n\n```text\ngithub.com/MrShiny608/terror/v2.TestPrintErrorTemplate\n\t",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "renders a standard go error",
+			instance: fmt.Errorf("root error"),
+			args: &args{
+				template: TextTemplate,
+			},
+			result: &result{
+				errString: "Cause: root error\n",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "renders a custom template",
+			instance: New(nil, New(nil, fmt.Errorf("root error"), WithStringInfo("key1", "value1")), WithStringInfo("key2", "value2")),
+			args:     &args{},
+			result: &result{
+				errString: "root error: layers=2 first=key2",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					args.template = template.Must(NewErrorTemplate("custom", `{{.Cause}}: layers={{len .Layers}} first={{(index (index .Layers 0).Infos 0).Key}}`))
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns the template's error",
+			instance: New(nil, fmt.Errorf("root error")),
+			args:     &args{},
+			result: &result{
+				errString: "",
+				hasError:  true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					args.template = template.Must(NewErrorTemplate("custom", `{{.Missing}}`))
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			errString, err := PrintErrorTemplate(instance, args.template)
+
+			// Assert
+			assert.Contains(t, errString, result.errString)
+			assert.Equal(t, result.hasError, err != nil)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestNewTemplateData(t *testing.T) {
+	t.Parallel()
+
+	sharedContext := &testContext{
+		additionalInfo: AdditionalInfos{WithStringInfo("context", "value")},
+	}
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+	}
+	type result struct {
+		layers []TemplateLayer
+		infos  []TemplateInfo
+	}
+	type testConfig struct {
+		name          string
+		instance      error
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "includes each layer, and a shared context's info only once",
+			instance: New(sharedContext, New(sharedContext, fmt.Errorf("root error"), WithIntInfo("b", 1)), WithIntInfo("a", 2)),
+			args:     &args{},
+			result: &result{
+				layers: []TemplateLayer{
+					{
+						ContextInfos: []TemplateInfo{{Key: "context", Value: "value"}},
+						Infos:        []TemplateInfo{{Key: "a", Value: int64(2)}},
+					},
+					{
+						ContextInfos: []TemplateInfo{},
+						Infos:        []TemplateInfo{{Key: "b", Value: int64(1)}},
+					},
+				},
+				infos: []TemplateInfo{
+					{Key: "a", Value: int64(2)},
+					{Key: "b", Value: int64(1)},
+					{Key: "context", Value: "value"},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "has no layers for a standard go error",
+			instance: fmt.Errorf("root error"),
+			args:     &args{},
+			result: &result{
+				layers: []TemplateLayer{},
+				infos:  []TemplateInfo{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			data := NewTemplateData(instance)
+
+			// Assert
+			assert.Equal(t, "root error", data.Cause)
+			assert.Equal(t, result.layers, data.Layers)
+			assert.Equal(t, result.infos, data.Infos)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestCodeMarkdown(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		text string
+	}
+	type result struct {
+		code string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "wraps text in a code span",
+			args:   &args{text: "key1"},
+			result: &result{code: "`key1`"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "uses a longer fence than the backticks in the text",
+			args:   &args{text: "a``b"},
+			result: &result{code: "```a``b```"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "pads text starting with a backtick",
+			args:   &args{text: "`a"},
+			result: &result{code: "`` `a ``"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "escapes pipes and newlines so it fits in a table cell",
+			args:   &args{text: "a|b\nc"},
+			result: &result{code: "`a\\|b c`"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			code := codeMarkdown(args.text)
+
+			// Assert
+			assert.Equal(t, result.code, code)
+
+			assertFunc(t)
+		})
+	}
+}
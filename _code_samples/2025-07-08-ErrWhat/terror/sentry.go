@@ -0,0 +1,316 @@
+package terror
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SentryEvent is an error in the Sentry event format, see https://develop.sentry.dev/sdk/data-model/event-payloads/
+type SentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Exception   SentryExceptions  `json:"exception"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+}
+
+type SentryExceptions struct {
+	Values []SentryException `json:"values"`
+}
+
+type SentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *SentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type SentryStacktrace struct {
+	// Frames are oldest first, i.e. the frame the error was created in is last
+	Frames []SentryFrame `json:"frames"`
+}
+
+type SentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryOptions struct {
+	tagKeys     []string
+	release     string
+	environment string
+	client      *http.Client
+}
+
+type SentryOption func(options *sentryOptions)
+
+// WithSentryTags sets the additional info keys sent as tags, which Sentry indexes for searching, the
+// rest of the additional info is sent as extra data. Only choose keys with a few distinct values.
+func WithSentryTags(keys ...string) (option SentryOption) {
+	return func(options *sentryOptions) {
+		options.tagKeys = keys
+	}
+}
+
+func WithSentryRelease(release string) (option SentryOption) {
+	return func(options *sentryOptions) {
+		options.release = release
+	}
+}
+
+func WithSentryEnvironment(environment string) (option SentryOption) {
+	return func(options *sentryOptions) {
+		options.environment = environment
+	}
+}
+
+// defaultSentryClient has a timeout, so an unreachable Sentry can't hold up the caller indefinitely
+var defaultSentryClient = &http.Client{Timeout: 10 * time.Second}
+
+// WithSentryClient sets the client events are sent with, the default times out after 10 seconds
+func WithSentryClient(client *http.Client) (option SentryOption) {
+	return func(options *sentryOptions) {
+		options.client = client
+	}
+}
+
+func getSentryOptions(options []SentryOption) (resolved *sentryOptions) {
+	resolved = &sentryOptions{
+		tagKeys: []string{KindKey.Name()},
+		client:  defaultSentryClient,
+	}
+	for _, option := range options {
+		option(resolved)
+	}
+
+	return resolved
+}
+
+// NewSentryEvent converts the error to a Sentry event. The exception values are the chain of
+// errors wrapped by the cause, oldest first as Sentry expects, with the callstack on the last.
+func NewSentryEvent(err error, options ...SentryOption) (event SentryEvent) {
+	resolved := getSentryOptions(options)
+
+	event = SentryEvent{
+		EventID:     newSentryEventID(),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       getSentryLevel(GetSeverity(err)),
+		Release:     resolved.release,
+		Environment: resolved.environment,
+		Fingerprint: []string{Fingerprint(err)},
+	}
+
+	cause := err
+	structuredError, isStructured := err.(*StructuredError)
+	for isStructured {
+		cause = structuredError.cause
+		structuredError, isStructured = cause.(*StructuredError)
+	}
+
+	for chained := cause; chained != nil; chained = errors.Unwrap(chained) {
+		event.Exception.Values = append(event.Exception.Values, SentryException{
+			Type:  fmt.Sprintf("%T", chained),
+			Value: chained.Error(),
+		})
+	}
+	slices.Reverse(event.Exception.Values)
+
+	structuredError, isStructured = err.(*StructuredError)
+	if !isStructured {
+		return event
+	}
+
+	// The ID is always a tag, so the event a user reports the ID of can be searched for
+	if structuredError.id != "" {
+		event.Tags = map[string]string{"error_id": structuredError.id}
+	}
+
+	moduleRoot := getModuleRoot()
+	frames := getFrames(structuredError.getOriginCallstack())
+	stacktrace := &SentryStacktrace{
+		Frames: make([]SentryFrame, 0, len(frames)),
+	}
+	for _, frame := range slices.Backward(frames) {
+		stacktrace.Frames = append(stacktrace.Frames, newSentryFrame(frame, moduleRoot))
+	}
+	if len(frames) > 0 {
+		event.Exception.Values[len(event.Exception.Values)-1].Stacktrace = stacktrace
+	}
+
+	for key, value := range structuredError.flattenAdditionalInfo().ToJSON() {
+		if slices.Contains(resolved.tagKeys, key) {
+			if event.Tags == nil {
+				event.Tags = make(map[string]string)
+			}
+			event.Tags[key] = fmt.Sprintf("%v", value)
+			continue
+		}
+
+		if event.Extra == nil {
+			event.Extra = make(map[string]any)
+		}
+		event.Extra[key] = value
+	}
+
+	return event
+}
+
+func newSentryFrame(frame runtime.Frame, moduleRoot string) (sentryFrame SentryFrame) {
+	// Split e.g. github.com/org/repo/pkg.(*Type).Method into the package and function
+	module := ""
+	function := frame.Function
+	lastSlash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[lastSlash+1:], ".")
+	if dot >= 0 {
+		module = function[:lastSlash+1+dot]
+		function = function[lastSlash+1+dot+1:]
+	}
+
+	return SentryFrame{
+		Function: function,
+		Module:   module,
+		Filename: shortenFrame(frame, moduleRoot).File,
+		AbsPath:  frame.File,
+		Lineno:   frame.Line,
+		InApp:    !isLibraryFrame(frame, moduleRoot),
+	}
+}
+
+func newSentryEventID() (eventID string) {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id) // #nosec G104 docs say this never returns an error
+
+	return hex.EncodeToString(id)
+}
+
+// SentrySink sends errors to a Sentry compatible endpoint, such as Sentry, a self-hosted Sentry or
+// a Relay, without the Sentry SDK
+type SentrySink struct {
+	dsn         string
+	envelopeURL string
+	publicKey   string
+	options     []SentryOption
+	client      *http.Client
+}
+
+// NewSentrySink parses the project's DSN, e.g. https://public_key@o0.ingest.sentry.io/project_id, the
+// project ID is the last segment of the path, and any before it are kept, e.g. for a self-hosted
+// Sentry behind https://public_key@example.com/sentry/project_id
+func NewSentrySink(dsn string, options ...SentryOption) (instance *SentrySink, err error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, New(nil, err, WithStringInfo("dsn", dsn))
+	}
+
+	publicKey := parsed.User.Username()
+	path := strings.TrimSuffix(parsed.Path, "/")
+	index := strings.LastIndex(path, "/")
+	prefix := path[:max(index, 0)]
+	projectID := path[index+1:]
+	if publicKey == "" || projectID == "" {
+		return nil, New(nil, fmt.Errorf("sentry dsn missing public key or project id"), WithStringInfo("dsn", dsn))
+	}
+
+	envelopeURL := url.URL{
+		Scheme: parsed.Scheme,
+		Host:   parsed.Host,
+		Path:   fmt.Sprintf("%s/api/%s/envelope/", prefix, projectID),
+	}
+
+	return &SentrySink{
+		dsn:         dsn,
+		envelopeURL: envelopeURL.String(),
+		publicKey:   publicKey,
+		options:     options,
+		client:      getSentryOptions(options).client,
+	}, nil
+}
+
+// Report sends the error, failures are dropped, use Send to handle them. Expected errors aren't
+// sent, see IsExpected.
+func (instance *SentrySink) Report(ctx context.Context, err error) {
+	if IsExpected(err) {
+		return
+	}
+
+	_ = instance.Send(ctx, err)
+}
+
+// Send sends the error as an event envelope, see https://develop.sentry.dev/sdk/data-model/envelopes/
+func (instance *SentrySink) Send(ctx context.Context, err error) (sendErr error) {
+	event := NewSentryEvent(err, instance.options...)
+
+	payload, sendErr := json.Marshal(event)
+	if sendErr != nil {
+		return New(nil, sendErr, WithStringInfo("event_id", event.EventID))
+	}
+	envelopeHeader, sendErr := json.Marshal(map[string]string{
+		"event_id": event.EventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+		"dsn":      instance.dsn,
+	})
+	if sendErr != nil {
+		return New(nil, sendErr, WithStringInfo("event_id", event.EventID))
+	}
+	itemHeader, sendErr := json.Marshal(map[string]any{
+		"type":   "event",
+		"length": len(payload),
+	})
+	if sendErr != nil {
+		return New(nil, sendErr, WithStringInfo("event_id", event.EventID))
+	}
+
+	body := bytes.Join([][]byte{envelopeHeader, itemHeader, payload, {}}, []byte("\n"))
+	request, sendErr := http.NewRequestWithContext(ctx, http.MethodPost, instance.envelopeURL, bytes.NewReader(body))
+	if sendErr != nil {
+		return New(nil, sendErr, WithStringInfo("event_id", event.EventID))
+	}
+	request.Header.Set("Content-Type", "application/x-sentry-envelope")
+	request.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=terror/2, sentry_key=%s", instance.publicKey))
+
+	response, sendErr := instance.client.Do(request)
+	if sendErr != nil {
+		return New(nil, sendErr, WithStringInfo("event_id", event.EventID))
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return New(nil, fmt.Errorf("sentry rejected event"), WithStringInfo("event_id", event.EventID), WithIntInfo("status_code", response.StatusCode))
+	}
+
+	return nil
+}
+
+// getSentryLevel maps the severity to a Sentry level, critical is fatal
+func getSentryLevel(severity Severity) (level string) {
+	switch severity {
+	case SeverityCritical:
+		return "fatal"
+	case SeverityDebug, SeverityInfo, SeverityWarning:
+		return string(severity)
+	default:
+		return "error"
+	}
+}
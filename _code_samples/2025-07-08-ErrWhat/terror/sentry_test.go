@@ -0,0 +1,296 @@
+package terror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSentryEvent(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err     error
+		options []SentryOption
+	}
+	type result struct {
+		level       string
+		exceptions  []SentryException
+		tags        map[string]string
+		extra       map[string]any
+		environment string
+		release     string
+		stacktrace  bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with wrapped cause and additional info",
+			args: &args{
+				err: New(nil, fmt.Errorf("query users: %w", errors.New("connection reset")),
+					KindKey.Info(KindUnavailable), WithStringInfo("database", "users"), WithIntInfo("attempt", 2)),
+				options: []SentryOption{WithSentryTags("kind", "database"), WithSentryEnvironment("production"), WithSentryRelease("v1.2.3")},
+			},
+			result: &result{
+				level: "error",
+				exceptions: []SentryException{
+					{Type: "*errors.errorString", Value: "connection reset"},
+					{Type: "*fmt.wrapError", Value: "query users: connection reset"},
+				},
+				tags: map[string]string{
+					"error_id": testErrorID,
+					"kind":     "unavailable",
+					"database": "users",
+				},
+				extra: map[string]any{
+					"attempt": int64(2),
+				},
+				environment: "production",
+				release:     "v1.2.3",
+				stacktrace:  true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with standard error",
+			args: &args{
+				err:     errors.New("standard error"),
+				options: []SentryOption{},
+			},
+			result: &result{
+				level: "error",
+				exceptions: []SentryException{
+					{Type: "*errors.errorString", Value: "standard error"},
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with critical severity",
+			args: &args{
+				err:     New(nil, errors.New("corrupt ledger"), SeverityKey.Info(SeverityCritical)),
+				options: []SentryOption{WithSentryTags("severity")},
+			},
+			result: &result{
+				level: "fatal",
+				exceptions: []SentryException{
+					{Type: "*errors.errorString", Value: "corrupt ledger"},
+				},
+				tags: map[string]string{
+					"error_id": testErrorID,
+					"severity": "critical",
+				},
+				stacktrace: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			event := NewSentryEvent(args.err, args.options...)
+
+			// Assert
+			assert.Len(t, event.EventID, 32)
+			assert.Equal(t, "go", event.Platform)
+			assert.Equal(t, result.level, event.Level)
+			assert.Equal(t, result.environment, event.Environment)
+			assert.Equal(t, result.release, event.Release)
+			assert.Equal(t, []string{Fingerprint(args.err)}, event.Fingerprint)
+			assert.Equal(t, result.tags, event.Tags)
+			assert.Equal(t, result.extra, event.Extra)
+
+			exceptions := event.Exception.Values
+			last := exceptions[len(exceptions)-1]
+			assert.Equal(t, result.stacktrace, last.Stacktrace != nil)
+			if result.stacktrace {
+				// Oldest first, so the frame which created the error is last
+				frames := last.Stacktrace.Frames
+				assert.Equal(t, "github.com/MrShiny608/terror/v2", frames[len(frames)-1].Module)
+				assert.True(t, strings.HasPrefix(frames[len(frames)-1].Function, "TestNewSentryEvent"))
+				assert.Equal(t, "sentry_test.go", frames[len(frames)-1].Filename)
+				assert.True(t, frames[len(frames)-1].InApp)
+				assert.Equal(t, "runtime", frames[0].Module)
+				assert.False(t, frames[0].InApp)
+			}
+
+			for i := range exceptions {
+				exceptions[i].Stacktrace = nil
+			}
+			assert.Equal(t, result.exceptions, exceptions)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestSentrySink(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		dsnPath    string
+		statusCode int
+	}
+	type result struct {
+		sendErr bool
+		path    string
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "with accepted event",
+			args:   &args{dsnPath: "/42", statusCode: http.StatusOK},
+			result: &result{sendErr: false, path: "/api/42/envelope/"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with rejected event",
+			args:   &args{dsnPath: "/42", statusCode: http.StatusTooManyRequests},
+			result: &result{sendErr: true, path: "/api/42/envelope/"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with path prefix",
+			args:   &args{dsnPath: "/sentry/42", statusCode: http.StatusOK},
+			result: &result{sendErr: false, path: "/sentry/api/42/envelope/"},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			var path, contentType, auth string
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				path = request.URL.Path
+				contentType = request.Header.Get("Content-Type")
+				auth = request.Header.Get("X-Sentry-Auth")
+				body, _ = io.ReadAll(request.Body)
+				writer.WriteHeader(args.statusCode)
+			}))
+			defer server.Close()
+
+			dsn := strings.Replace(server.URL, "http://", "http://public@", 1) + args.dsnPath
+			instance, err := NewSentrySink(dsn, WithSentryClient(server.Client()))
+			assert.NoError(t, err)
+
+			// Act
+			actFunc(t)
+			sendErr := instance.Send(context.Background(), New(nil, fmt.Errorf("root error"), KindKey.Info(KindInternal)))
+
+			// Assert
+			assert.Equal(t, result.sendErr, sendErr != nil)
+			assert.Equal(t, result.path, path)
+			assert.Equal(t, "application/x-sentry-envelope", contentType)
+			assert.Contains(t, auth, "sentry_key=public")
+
+			lines := bytes.Split(bytes.TrimSuffix(body, []byte("\n")), []byte("\n"))
+			assert.Len(t, lines, 3)
+
+			envelopeHeader := map[string]string{}
+			assert.NoError(t, json.Unmarshal(lines[0], &envelopeHeader))
+			assert.Equal(t, dsn, envelopeHeader["dsn"])
+
+			itemHeader := map[string]any{}
+			assert.NoError(t, json.Unmarshal(lines[1], &itemHeader))
+			assert.Equal(t, "event", itemHeader["type"])
+			assert.Equal(t, float64(len(lines[2])), itemHeader["length"])
+
+			event := SentryEvent{}
+			assert.NoError(t, json.Unmarshal(lines[2], &event))
+			assert.Equal(t, envelopeHeader["event_id"], event.EventID)
+			assert.Equal(t, "root error", event.Exception.Values[0].Value)
+			assert.Equal(t, map[string]string{"error_id": testErrorID, "kind": "internal"}, event.Tags)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestSentrySinkExpected(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "http://", "http://public@", 1) + "/42"
+	instance, err := NewSentrySink(dsn, WithSentryClient(server.Client()))
+	assert.NoError(t, err)
+
+	// Act
+	instance.Report(context.Background(), New(nil, fmt.Errorf("user not found"), ExpectedKey.Info(true)))
+	instance.Report(context.Background(), New(nil, fmt.Errorf("connection reset")))
+
+	// Assert
+	// Only the unexpected error is sent
+	assert.Equal(t, 1, requests)
+}
+
+func TestNewSentrySinkInvalidDSN(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	instance, err := NewSentrySink("https://sentry.example.com")
+
+	// Assert
+	assert.Nil(t, instance)
+	assert.EqualError(t, err, "sentry dsn missing public key or project id")
+}
@@ -0,0 +1,123 @@
+package terror
+
+import (
+	"log/slog"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+)
+
+// Severity is how urgently an error needs attention, so a client validation failure isn't routed
+// like data corruption
+type Severity string
+
+const (
+	SeverityDebug    Severity = "debug"
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityError    Severity = "error"
+	SeverityCritical Severity = "critical"
+)
+
+var severityRanks = map[Severity]int{
+	SeverityDebug:    1,
+	SeverityInfo:     2,
+	SeverityWarning:  3,
+	SeverityError:    4,
+	SeverityCritical: 5,
+}
+
+// SeverityKey records the Severity as additional info, the most severe in the chain is the error's
+// severity, so wrapping can't make an error less urgent
+//
+//	err = terror.New(ctx, err, terror.SeverityKey.Info(terror.SeverityCritical))
+var SeverityKey = NewKey[Severity]("severity")
+
+// SeverityOverrideKey replaces the severity of the chain, e.g. where a caller knows a failure is
+// expected, and the outermost override wins
+var SeverityOverrideKey = NewKey[Severity]("severity_override")
+
+// GetSeverity returns the outermost override, otherwise SeverityInfo for an expected error, see
+// IsExpected, otherwise the most severe Severity in the chain, and SeverityError when none was set.
+// Unknown severities are ignored.
+func GetSeverity(err error) (severity Severity) {
+	for _, override := range LookupAll[Severity](err, SeverityOverrideKey.Name()) {
+		_, known := severityRanks[override]
+		if known {
+			return override
+		}
+	}
+
+	if IsExpected(err) {
+		return SeverityInfo
+	}
+
+	severity = ""
+	for _, value := range LookupAll[Severity](err, SeverityKey.Name()) {
+		if severityRanks[value] > severityRanks[severity] {
+			severity = value
+		}
+	}
+	if severity == "" {
+		return SeverityError
+	}
+
+	return severity
+}
+
+// AtLeast reports whether the severity is as severe as minimum, unknown severities are never
+func (instance Severity) AtLeast(minimum Severity) (atLeast bool) {
+	rank, known := severityRanks[instance]
+
+	return known && rank >= severityRanks[minimum]
+}
+
+// SlogLevel maps the severity to a slog.Level, critical is above slog.LevelError
+func (instance Severity) SlogLevel() (level slog.Level) {
+	switch instance {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError
+	}
+}
+
+// LogSeverity maps the severity to an otel log severity, critical is fatal
+func (instance Severity) LogSeverity() (severity log.Severity) {
+	switch instance {
+	case SeverityDebug:
+		return log.SeverityDebug
+	case SeverityInfo:
+		return log.SeverityInfo
+	case SeverityWarning:
+		return log.SeverityWarn
+	case SeverityCritical:
+		return log.SeverityFatal
+	default:
+		return log.SeverityError
+	}
+}
+
+// SpanStatus maps the severity to a span status, only errors and above fail the span, below that the
+// status is left unset
+func (instance Severity) SpanStatus() (code codes.Code) {
+	_, known := severityRanks[instance]
+	if known && !instance.AtLeast(SeverityError) {
+		return codes.Unset
+	}
+
+	return codes.Error
+}
+
+// MatchSeverity matches errors at least as severe as minimum, for Filter
+func MatchSeverity(minimum Severity) (match func(err error) bool) {
+	return func(err error) bool {
+		return GetSeverity(err).AtLeast(minimum)
+	}
+}
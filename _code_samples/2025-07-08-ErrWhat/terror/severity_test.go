@@ -0,0 +1,207 @@
+package terror
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+)
+
+func TestGetSeverity(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+	}
+	type result struct {
+		severity Severity
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "with no severity",
+			args:   &args{err: New(nil, fmt.Errorf("root error"))},
+			result: &result{severity: SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with standard error",
+			args:   &args{err: fmt.Errorf("root error")},
+			result: &result{severity: SeverityError},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with the most severe in the chain",
+			args: &args{
+				err: New(nil, New(nil, fmt.Errorf("root error"), SeverityKey.Info(SeverityCritical)), SeverityKey.Info(SeverityWarning)),
+			},
+			result: &result{severity: SeverityCritical},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with severity below error",
+			args: &args{
+				err: New(nil, New(nil, fmt.Errorf("root error"), SeverityKey.Info(SeverityInfo)), SeverityKey.Info(SeverityWarning)),
+			},
+			result: &result{severity: SeverityWarning},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with the outermost override",
+			args: &args{
+				err: New(nil, New(nil, fmt.Errorf("root error"), SeverityKey.Info(SeverityCritical), SeverityOverrideKey.Info(SeverityWarning)), SeverityOverrideKey.Info(SeverityInfo)),
+			},
+			result: &result{severity: SeverityInfo},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with unknown severities ignored",
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), SeverityKey.Info("urgent"), SeverityKey.Info(SeverityDebug), SeverityOverrideKey.Info("ignore")),
+			},
+			result: &result{severity: SeverityDebug},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			severity := GetSeverity(args.err)
+
+			// Assert
+			assert.Equal(t, result.severity, severity)
+			assert.True(t, MatchSeverity(result.severity)(args.err))
+			assert.Equal(t, result.severity != SeverityCritical, !MatchSeverity(SeverityCritical)(args.err))
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestSeverityMappings(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		severity Severity
+	}
+	type result struct {
+		level       slog.Level
+		logSeverity log.Severity
+		spanStatus  codes.Code
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:   "with debug",
+			args:   &args{severity: SeverityDebug},
+			result: &result{level: slog.LevelDebug, logSeverity: log.SeverityDebug, spanStatus: codes.Unset},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with info",
+			args:   &args{severity: SeverityInfo},
+			result: &result{level: slog.LevelInfo, logSeverity: log.SeverityInfo, spanStatus: codes.Unset},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with warning",
+			args:   &args{severity: SeverityWarning},
+			result: &result{level: slog.LevelWarn, logSeverity: log.SeverityWarn, spanStatus: codes.Unset},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with error",
+			args:   &args{severity: SeverityError},
+			result: &result{level: slog.LevelError, logSeverity: log.SeverityError, spanStatus: codes.Error},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with critical",
+			args:   &args{severity: SeverityCritical},
+			result: &result{level: slog.LevelError + 4, logSeverity: log.SeverityFatal, spanStatus: codes.Error},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:   "with unknown",
+			args:   &args{severity: "urgent"},
+			result: &result{level: slog.LevelError, logSeverity: log.SeverityError, spanStatus: codes.Error},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			level := args.severity.SlogLevel()
+			logSeverity := args.severity.LogSeverity()
+			spanStatus := args.severity.SpanStatus()
+
+			// Assert
+			assert.Equal(t, result.level, level)
+			assert.Equal(t, result.logSeverity, logSeverity)
+			assert.Equal(t, result.spanStatus, spanStatus)
+
+			assertFunc(t)
+		})
+	}
+}
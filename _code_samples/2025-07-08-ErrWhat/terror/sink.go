@@ -0,0 +1,197 @@
+package terror
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Sink is somewhere errors are reported to, e.g. a log, an error tracker or a test. Sinks compose
+// into a pipeline, e.g.
+//
+//	sink := terror.Enrich(terror.FanOut(
+//		terror.NewStdoutSink(),
+//		terror.Filter(trackerSink, terror.MatchKind(terror.KindInternal)),
+//	), terror.ProcessInfo()...)
+type Sink interface {
+	Report(ctx context.Context, err error)
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc func(ctx context.Context, err error)
+
+func (instance SinkFunc) Report(ctx context.Context, err error) {
+	instance(ctx, err)
+}
+
+// FanOut reports each error to every sink, in order
+func FanOut(sinks ...Sink) (sink Sink) {
+	return SinkFunc(func(ctx context.Context, err error) {
+		for _, sink := range sinks {
+			sink.Report(ctx, err)
+		}
+	})
+}
+
+// Filter reports only the errors match returns true for to the sink
+func Filter(sink Sink, match func(err error) bool) (filtered Sink) {
+	return SinkFunc(func(ctx context.Context, err error) {
+		if match(err) {
+			sink.Report(ctx, err)
+		}
+	})
+}
+
+// MatchKind matches errors of any of the kinds, for Filter
+func MatchKind(kinds ...Kind) (match func(err error) bool) {
+	return func(err error) bool {
+		return slices.Contains(kinds, GetKind(err))
+	}
+}
+
+// MatchKey matches errors with additional info of the key, for Filter
+func MatchKey(key string) (match func(err error) bool) {
+	return func(err error) bool {
+		_, found := Lookup[any](err, key)
+
+		return found
+	}
+}
+
+// sampleMaxFingerprints caps the fingerprints Sample counts, so messages which aren't normalized
+// can't grow memory without bound
+const sampleMaxFingerprints = 10000
+
+type sampleCount struct {
+	fingerprint string
+	count       uint64
+}
+
+// Sample reports the first error with each Fingerprint to the sink, then every nth, so a failure
+// happening in a loop can't flood the sink but a new failure is never dropped. The counts of up to
+// 10000 fingerprints are held, the least recently seen is forgotten to make room for a new one, and
+// is reported as new if it recurs.
+func Sample(sink Sink, n uint64, options ...FingerprintOption) (sampled Sink) {
+	return newSample(sink, n, sampleMaxFingerprints, options)
+}
+
+func newSample(sink Sink, n uint64, maxFingerprints int, options []FingerprintOption) (sampled Sink) {
+	lock := sync.Mutex{}
+	// recency is most recently seen first, so the least recently seen is forgotten without a scan
+	recency := list.New()
+	counts := make(map[string]*list.Element)
+
+	return SinkFunc(func(ctx context.Context, err error) {
+		fingerprint := Fingerprint(err, options...)
+
+		lock.Lock()
+		element, found := counts[fingerprint]
+		if found {
+			recency.MoveToFront(element)
+		} else {
+			if recency.Len() >= maxFingerprints {
+				leastRecent := recency.Remove(recency.Back()).(*sampleCount)
+				delete(counts, leastRecent.fingerprint)
+			}
+			element = recency.PushFront(&sampleCount{fingerprint: fingerprint})
+			counts[fingerprint] = element
+		}
+		entry := element.Value.(*sampleCount)
+		count := entry.count
+		entry.count++
+		lock.Unlock()
+
+		if n <= 1 || count%n == 0 {
+			sink.Report(ctx, err)
+		}
+	})
+}
+
+// Enrich adds the infos to each error before reporting it to the sink, at a lower precedence than
+// the error's own additional info. The error keeps its callstack, ID and Fingerprint.
+func Enrich(sink Sink, additionalInfo ...AdditionalInfo) (enriched Sink) {
+	return SinkFunc(func(ctx context.Context, err error) {
+		sink.Report(ctx, newEnrichedLayer(err, additionalInfo))
+	})
+}
+
+// ProcessInfo returns the process ID and Go version along with BuildInfo, for Enrich
+func ProcessInfo() (additionalInfo AdditionalInfos) {
+	additionalInfo = AdditionalInfos{
+		WithIntInfo("pid", os.Getpid()),
+		WithStringInfo("go_version", runtime.Version()),
+	}
+
+	return append(additionalInfo, BuildInfo()...)
+}
+
+// WriterSink writes each error to the writer on a line of its own
+type WriterSink struct {
+	lock   sync.Mutex
+	writer io.Writer
+	print  func(err error) string
+}
+
+// NewWriterSink writes each error rendered by print, e.g. PrintErrorLine
+func NewWriterSink(writer io.Writer, print func(err error) string) (instance *WriterSink) {
+	return &WriterSink{
+		writer: writer,
+		print:  print,
+	}
+}
+
+// NewStdoutSink writes each error to stdout as a logfmt line, see PrintErrorLogfmt. Expected errors
+// are skipped, see IsExpected, use NewWriterSink to write them too.
+func NewStdoutSink() (instance Sink) {
+	return Filter(NewWriterSink(os.Stdout, func(err error) string {
+		return PrintErrorLogfmt(err)
+	}), MatchUnexpected())
+}
+
+func (instance *WriterSink) Report(ctx context.Context, err error) {
+	line := instance.print(err)
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	_, _ = fmt.Fprintln(instance.writer, line)
+}
+
+// MemorySink holds the errors reported to it, for tests
+type MemorySink struct {
+	lock sync.Mutex
+	errs []error
+}
+
+func NewMemorySink() (instance *MemorySink) {
+	return &MemorySink{
+		errs: make([]error, 0),
+	}
+}
+
+func (instance *MemorySink) Report(ctx context.Context, err error) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	instance.errs = append(instance.errs, err)
+}
+
+// Errors returns the errors reported so far, in order
+func (instance *MemorySink) Errors() (errs []error) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	return slices.Clone(instance.errs)
+}
+
+func (instance *MemorySink) Reset() {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	instance.errs = instance.errs[:0]
+}
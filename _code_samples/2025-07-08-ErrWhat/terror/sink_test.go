@@ -0,0 +1,305 @@
+package terror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		errs []error
+	}
+	type result struct {
+		causes []string
+	}
+	type testConfig struct {
+		name          string
+		newSink       func(memorySink *MemorySink) (sink Sink)
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "fans out to every sink",
+			newSink: func(memorySink *MemorySink) (sink Sink) {
+				return FanOut(memorySink, memorySink)
+			},
+			args: &args{
+				errs: []error{
+					newFingerprintError("first error"),
+				},
+			},
+			result: &result{
+				causes: []string{"first error", "first error"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "filters by kind",
+			newSink: func(memorySink *MemorySink) (sink Sink) {
+				return Filter(memorySink, MatchKind(KindInternal, KindUnavailable))
+			},
+			args: &args{
+				errs: []error{
+					newFingerprintError("user not found", KindKey.Info(KindNotFound)),
+					newFingerprintError("database down", KindKey.Info(KindUnavailable)),
+					newFingerprintError("no kind"),
+				},
+			},
+			result: &result{
+				causes: []string{"database down"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "filters by key",
+			newSink: func(memorySink *MemorySink) (sink Sink) {
+				return Filter(memorySink, MatchKey("tenant_id"))
+			},
+			args: &args{
+				errs: []error{
+					newFingerprintError("with tenant", WithStringInfo("tenant_id", "tenant")),
+					newFingerprintError("without tenant"),
+				},
+			},
+			result: &result{
+				causes: []string{"with tenant"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "samples the first and every nth error by fingerprint",
+			newSink: func(memorySink *MemorySink) (sink Sink) {
+				return Sample(memorySink, 2)
+			},
+			args: &args{
+				errs: []error{
+					newFingerprintError("user 1 not found"),
+					newFingerprintError("user 2 not found"),
+					newOtherFingerprintError("database down"),
+					newFingerprintError("user 3 not found"),
+					newFingerprintError("user 4 not found"),
+				},
+			},
+			result: &result{
+				causes: []string{"user 1 not found", "database down", "user 3 not found"},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			memorySink := NewMemorySink()
+			sink := config.newSink(memorySink)
+
+			// Act
+			actFunc(t)
+			for _, err := range args.errs {
+				sink.Report(context.Background(), err)
+			}
+
+			// Assert
+			causes := make([]string, 0)
+			for _, err := range memorySink.Errors() {
+				causes = append(causes, err.Error())
+			}
+			assert.Equal(t, result.causes, causes)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestSampleBound(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	memorySink := NewMemorySink()
+	sink := newSample(memorySink, 2, 2, []FingerprintOption{})
+
+	// Act
+	for _, message := range []string{"alpha", "bravo", "alpha", "charlie", "bravo", "alpha"} {
+		sink.Report(context.Background(), errors.New(message))
+	}
+
+	// Assert
+	causes := make([]string, 0)
+	for _, err := range memorySink.Errors() {
+		causes = append(causes, err.Error())
+	}
+	// charlie forgets bravo, the least recently seen, so bravo is new again and forgets alpha
+	assert.Equal(t, []string{"alpha", "bravo", "charlie", "bravo", "alpha"}, causes)
+}
+
+func TestEnrich(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		err error
+	}
+	type result struct {
+		additionalInfo map[string]any
+		id             string
+		foundID        bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "with structured error",
+			args: &args{
+				err: New(nil, fmt.Errorf("root error"), WithStringInfo("version", "v2.0.0")),
+			},
+			result: &result{
+				additionalInfo: map[string]any{
+					"hostname": "host",
+					"version":  "v2.0.0",
+				},
+				id:      testErrorID,
+				foundID: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "with standard error",
+			args: &args{
+				err: fmt.Errorf("root error"),
+			},
+			result: &result{
+				additionalInfo: map[string]any{
+					"hostname": "host",
+					"version":  "v1.0.0",
+				},
+				id:      "",
+				foundID: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			memorySink := NewMemorySink()
+			sink := Enrich(memorySink, WithStringInfo("hostname", "host"), WithStringInfo("version", "v1.0.0"))
+
+			// Act
+			actFunc(t)
+			sink.Report(context.Background(), args.err)
+
+			// Assert
+			assert.Len(t, memorySink.Errors(), 1)
+			enriched := memorySink.Errors()[0]
+			_, callstack, additionalInfo := GetLoggingInfo(enriched)
+			_, expectedCallstack, _ := GetLoggingInfo(args.err)
+			assert.Equal(t, result.additionalInfo, additionalInfo.ToJSON())
+			// Enriching mustn't capture a callstack in the sink, or change how the error is grouped
+			assert.Equal(t, expectedCallstack, callstack)
+			assert.NotContains(t, PrintError(enriched), "sink.go")
+			assert.Equal(t, Fingerprint(args.err), Fingerprint(enriched))
+
+			id, found := GetErrorID(enriched)
+			assert.Equal(t, result.id, id)
+			assert.Equal(t, result.foundID, found)
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestProcessInfo(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	additionalInfo := ProcessInfo().ToJSON()
+
+	// Assert
+	assert.Equal(t, int64(os.Getpid()), additionalInfo["pid"])
+	assert.Equal(t, runtime.Version(), additionalInfo["go_version"])
+
+	hostname, err := os.Hostname()
+	if err == nil {
+		assert.Equal(t, hostname, additionalInfo["hostname"])
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	buffer := &bytes.Buffer{}
+	sink := NewWriterSink(buffer, func(err error) string {
+		return PrintErrorLogfmt(err, WithMaxFrames(1))
+	})
+	err := New(nil, fmt.Errorf("root error"), WithStringInfo("key", "value"))
+
+	// Act
+	sink.Report(context.Background(), err)
+	sink.Report(context.Background(), err)
+
+	// Assert
+	line := PrintErrorLogfmt(err, WithMaxFrames(1)) + "\n"
+	assert.Equal(t, line+line, buffer.String())
+}
+
+func TestMemorySink(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	sink := NewMemorySink()
+	sink.Report(context.Background(), fmt.Errorf("first error"))
+
+	// Act
+	sink.Reset()
+	sink.Report(context.Background(), fmt.Errorf("second error"))
+
+	// Assert
+	assert.Len(t, sink.Errors(), 1)
+	assert.EqualError(t, sink.Errors()[0], "second error")
+}
@@ -0,0 +1,101 @@
+package terror
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// StackExtractor returns the program counters of the callstack an error from another library
+// carries, so New can keep the stack of where the failure started rather than capturing its own
+type StackExtractor func(err error) (callstack []uintptr, found bool)
+
+var (
+	stackExtractorsLock sync.RWMutex
+	stackExtractors     = []*StackExtractor{
+		newStackExtractor(extractPkgErrorsCallstack),
+		newStackExtractor(extractCallersCallstack),
+	}
+)
+
+func newStackExtractor(extractor StackExtractor) (entry *StackExtractor) {
+	return &extractor
+}
+
+// RegisterStackExtractor adds an extractor, they are tried in registration order after the built-in
+// extractors for github.com/pkg/errors style StackTrace() and github.com/go-errors/errors style Callers().
+// unregister removes it again, e.g. in a test's cleanup.
+func RegisterStackExtractor(extractor StackExtractor) (unregister func()) {
+	stackExtractorsLock.Lock()
+	defer stackExtractorsLock.Unlock()
+
+	entry := newStackExtractor(extractor)
+	stackExtractors = append(slices.Clone(stackExtractors), entry)
+
+	return func() {
+		stackExtractorsLock.Lock()
+		defer stackExtractorsLock.Unlock()
+
+		stackExtractors = slices.DeleteFunc(slices.Clone(stackExtractors), func(registered *StackExtractor) bool {
+			return registered == entry
+		})
+	}
+}
+
+// getCauseCallstack finds the deepest stack in the cause's chain, as each wrapping layer may add
+// another stack, and the deepest is the one closest to the origin of the failure
+func getCauseCallstack(cause error) (callstack []uintptr, found bool) {
+	// The extractors are called without the lock, as they may create errors or register extractors
+	stackExtractorsLock.RLock()
+	extractors := stackExtractors
+	stackExtractorsLock.RUnlock()
+
+	for cause != nil {
+		for _, extractor := range extractors {
+			causeCallstack, ok := (*extractor)(cause)
+			if ok {
+				callstack = causeCallstack
+				found = true
+				break
+			}
+		}
+
+		cause = errors.Unwrap(cause)
+	}
+
+	return callstack, found
+}
+
+type pkgErrorsStackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+func extractPkgErrorsCallstack(err error) (callstack []uintptr, found bool) {
+	stackTracer, ok := err.(pkgErrorsStackTracer)
+	if !ok {
+		return nil, false
+	}
+
+	stackTrace := stackTracer.StackTrace()
+	callstack = make([]uintptr, len(stackTrace))
+	for i, frame := range stackTrace {
+		callstack[i] = uintptr(frame)
+	}
+
+	return callstack, true
+}
+
+type callersProvider interface {
+	Callers() []uintptr
+}
+
+func extractCallersCallstack(err error) (callstack []uintptr, found bool) {
+	provider, ok := err.(callersProvider)
+	if !ok {
+		return nil, false
+	}
+
+	return provider.Callers(), true
+}
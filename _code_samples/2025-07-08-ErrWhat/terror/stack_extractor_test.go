@@ -0,0 +1,202 @@
+package terror
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPkgError returns a pkg/errors error, along with the line it was created on
+func newPkgError() (err error, line string) {
+	_, file, lineNumber, _ := runtime.Caller(0)
+	err = pkgerrors.New("root error")
+
+	return err, fmt.Sprintf("%s: %d", file, lineNumber+1)
+}
+
+type callersError struct {
+	programCounters []uintptr
+}
+
+func (e *callersError) Error() string {
+	return "root error"
+}
+
+func (e *callersError) Callers() []uintptr {
+	return e.programCounters
+}
+
+// newCallersError returns an error providing its callers, along with the line it was created on
+func newCallersError() (err error, line string) {
+	programCounters := make([]uintptr, 32)
+	count := runtime.Callers(1, programCounters)
+	_, file, lineNumber, _ := runtime.Caller(0)
+
+	return &callersError{programCounters: programCounters[:count]}, fmt.Sprintf("%s: %d", file, lineNumber-1)
+}
+
+type registeredStackError struct {
+	programCounters []uintptr
+}
+
+func (e *registeredStackError) Error() string {
+	return "root error"
+}
+
+func TestGetCauseCallstack(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		cause error
+	}
+	type result struct {
+		callstack string
+		found     bool
+	}
+	type testConfig struct {
+		name          string
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name: "returns the callstack of a pkg/errors error",
+			args: &args{},
+			result: &result{
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					args.cause, result.callstack = newPkgError()
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns the deepest callstack when wrapped by pkg/errors and fmt.Errorf",
+			args: &args{},
+			result: &result{
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					var cause error
+					cause, result.callstack = newPkgError()
+					args.cause = fmt.Errorf("wrapped: %w", pkgerrors.Wrap(cause, "wrapped"))
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns the callstack of an error providing its callers",
+			args: &args{},
+			result: &result{
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					args.cause, result.callstack = newCallersError()
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns the callstack from a registered extractor",
+			args: &args{},
+			result: &result{
+				found: true,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					unregister := RegisterStackExtractor(func(err error) (callstack []uintptr, found bool) {
+						e, ok := err.(*registeredStackError)
+						if !ok {
+							return nil, false
+						}
+
+						return e.programCounters, true
+					})
+					t.Cleanup(unregister)
+
+					cause, line := newCallersError()
+					args.cause = &registeredStackError{programCounters: cause.(*callersError).programCounters}
+					result.callstack = line
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "returns not found for a standard go error",
+			args: &args{
+				cause: fmt.Errorf("root error"),
+			},
+			result: &result{
+				found: false,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			callstack, found := getCauseCallstack(args.cause)
+
+			// Assert
+			assert.Equal(t, result.found, found)
+			if found {
+				assert.Equal(t, result.callstack, formatCallstack(callstack)[0])
+				assert.Equal(t, result.callstack, formatCallstack(New(nil, args.cause).callstack)[0])
+			}
+
+			assertFunc(t)
+		})
+	}
+}
+
+type reentrantStackError struct{}
+
+func (e *reentrantStackError) Error() string {
+	return "root error"
+}
+
+func TestRegisterStackExtractor(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	unregister := RegisterStackExtractor(func(err error) (callstack []uintptr, found bool) {
+		_, ok := err.(*reentrantStackError)
+		if !ok {
+			return nil, false
+		}
+
+		// Extractors may create errors and register extractors without deadlocking
+		RegisterStackExtractor(func(err error) (callstack []uintptr, found bool) {
+			return nil, false
+		})()
+
+		return New(nil, fmt.Errorf("inner error")).callstack, true
+	})
+
+	// Act
+	_, found := getCauseCallstack(&reentrantStackError{})
+	unregister()
+	_, foundAfterUnregister := getCauseCallstack(&reentrantStackError{})
+
+	// Assert
+	assert.True(t, found)
+	assert.False(t, foundAfterUnregister)
+}
@@ -0,0 +1,22 @@
+package terror
+
+import (
+	pkgerrors "github.com/pkg/errors"
+)
+
+// StackTrace returns the callstack of where the error originated, in the form github.com/pkg/errors
+// uses, so tooling which understands pkg/errors (error reporters, log formatters etc) can show it
+func (instance *StructuredError) StackTrace() (stackTrace pkgerrors.StackTrace) {
+	callstack := instance.getOriginCallstack()
+	stackTrace = make(pkgerrors.StackTrace, len(callstack))
+	for i, programCounter := range callstack {
+		stackTrace[i] = pkgerrors.Frame(programCounter)
+	}
+
+	return stackTrace
+}
+
+// Cause returns the error this layer wraps, for github.com/pkg/errors.Cause
+func (instance *StructuredError) Cause() (cause error) {
+	return instance.cause
+}
@@ -0,0 +1,164 @@
+package terror
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackTrace(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+	}
+	type result struct {
+		frame string
+	}
+	type testConfig struct {
+		name          string
+		instance      *StructuredError
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "returns the frames of the callstack",
+			instance: New(nil, fmt.Errorf("root error")),
+			args:     &args{},
+			result: &result{
+				frame: "",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					// Find the current file path
+					_, currentFilePath, _, ok := runtime.Caller(1)
+					assert.True(t, ok)
+
+					result.frame = currentFilePath
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns the frames of the innermost error",
+			instance: New(nil, New(nil, fmt.Errorf("root error"))),
+			args:     &args{},
+			result: &result{
+				frame: "",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					// Find the current file path
+					_, currentFilePath, _, ok := runtime.Caller(1)
+					assert.True(t, ok)
+
+					result.frame = currentFilePath
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			stackTrace := (*StructuredError).StackTrace(instance)
+
+			// Assert
+			assert.NotEmpty(t, stackTrace)
+			assert.Contains(t, fmt.Sprintf("%+v", stackTrace[0]), result.frame)
+
+			var stackTracer pkgErrorsStackTracer = instance
+			assert.Equal(t, stackTrace, stackTracer.StackTrace())
+
+			assertFunc(t)
+		})
+	}
+}
+
+func TestCause(t *testing.T) {
+	t.Parallel()
+
+	rootError := fmt.Errorf("root error")
+	innerError := New(nil, rootError)
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+	}
+	type result struct {
+		cause     error
+		rootCause error
+	}
+	type testConfig struct {
+		name          string
+		instance      *StructuredError
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "returns the wrapped error",
+			instance: innerError,
+			args:     &args{},
+			result: &result{
+				cause:     rootError,
+				rootCause: rootError,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "returns the wrapped structured error, which pkg/errors follows to the root",
+			instance: New(nil, innerError),
+			args:     &args{},
+			result: &result{
+				cause:     innerError,
+				rootCause: rootError,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			cause := (*StructuredError).Cause(instance)
+			rootCause := pkgerrors.Cause(instance)
+
+			// Assert
+			assert.Equal(t, result.cause, cause)
+			assert.Equal(t, result.rootCause, rootCause)
+
+			assertFunc(t)
+		})
+	}
+}
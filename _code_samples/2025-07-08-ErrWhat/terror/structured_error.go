@@ -3,6 +3,7 @@ package terror
 import (
 	"errors"
 	"fmt"
+	"io"
 	"runtime"
 	"slices"
 	"strings"
@@ -15,25 +16,31 @@ type StructuredContext interface {
 type StructuredError struct {
 	context        StructuredContext
 	cause          error
-	callstack      []string
+	callstack      []uintptr
 	additionalInfo AdditionalInfos
+	layerInfo      LayerInfo
+	id             string
 }
 
 func New(ctx StructuredContext, cause error, additionalInfo ...AdditionalInfo) (err *StructuredError) {
-	var callstack []string
-	switch cause.(type) {
+	var callstack []uintptr
+	var id string
+	switch c := cause.(type) {
 	case *StructuredError:
-		// Don't generate the callstack multiple times
+		// Don't generate the callstack multiple times, and keep the ID the error originated with
+		id = c.id
 	default:
-		callstack = make([]string, 0)
-		stackDepth := 1
-		for stackDepth < 1000 {
-			_, file, line, success := runtime.Caller(stackDepth)
-			if !success {
-				break
-			}
-			stackDepth++
-			callstack = append(callstack, fmt.Sprintf("%s: %d", file, line))
+		// Keep the ID of an error wrapped by another type, e.g. fmt.Errorf with %w
+		var found bool
+		id, found = GetErrorID(cause)
+		if !found {
+			id = generateID()
+		}
+
+		// If the cause already carries a stack, that's where the failure really started
+		callstack, found = getCauseCallstack(cause)
+		if !found {
+			callstack = captureCallstack(3)
 		}
 	}
 
@@ -42,13 +49,92 @@ func New(ctx StructuredContext, cause error, additionalInfo ...AdditionalInfo) (
 		cause:          cause,
 		callstack:      callstack,
 		additionalInfo: additionalInfo,
+		layerInfo:      newLayerInfo(),
+		id:             id,
 	}
 }
 
+// newEnrichedLayer adds additional info to the cause without a callstack or ID of its own, keeping
+// the origin of a StructuredError cause, so the error reports with the same callstack, ID and
+// Fingerprint as it did before, e.g. for Enrich
+func newEnrichedLayer(cause error, additionalInfo AdditionalInfos) (err *StructuredError) {
+	err = &StructuredError{
+		cause:          cause,
+		additionalInfo: additionalInfo,
+	}
+
+	structuredError, isStructured := cause.(*StructuredError)
+	if isStructured {
+		err.layerInfo = structuredError.layerInfo
+		err.id = structuredError.id
+	}
+
+	return err
+}
+
+// captureCallstack records the program counters of the stack, skipping the given number of frames,
+// where 0 is runtime.Callers itself
+func captureCallstack(skip int) (callstack []uintptr) {
+	programCounters := make([]uintptr, 64)
+	count := runtime.Callers(skip, programCounters)
+	for count == len(programCounters) && len(programCounters) < 1000 {
+		programCounters = make([]uintptr, len(programCounters)*2)
+		count = runtime.Callers(skip, programCounters)
+	}
+
+	return slices.Clip(programCounters[:count])
+}
+
+func getFrames(callstack []uintptr) (frames []runtime.Frame) {
+	frames = make([]runtime.Frame, 0, len(callstack))
+	callersFrames := runtime.CallersFrames(callstack)
+	for {
+		frame, more := callersFrames.Next()
+		if frame.PC != 0 {
+			frames = append(frames, frame)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+func formatFrame(frame runtime.Frame) (line string) {
+	return fmt.Sprintf("%s: %d", frame.File, frame.Line)
+}
+
+func formatCallstack(callstack []uintptr) (lines []string) {
+	frames := getFrames(callstack)
+	lines = make([]string, len(frames))
+	for i, frame := range frames {
+		lines[i] = formatFrame(frame)
+	}
+
+	return lines
+}
+
 func (instance *StructuredError) Error() (message string) {
 	return instance.cause.Error()
 }
 
+// Format implements fmt.Formatter, %v and %s give the message as with any other error, %+v gives the
+// PrintError output with additional info and callstack, and %#v dumps the layer for debugging
+func (instance *StructuredError) Format(state fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && state.Flag('+'):
+		_, _ = io.WriteString(state, strings.TrimSuffix(PrintError(instance), "\n"))
+	case verb == 'v' && state.Flag('#'):
+		_, _ = fmt.Fprintf(state, "&terror.StructuredError{cause:%#v, additionalInfo:%#v, callstack:%#v}",
+			instance.cause, instance.additionalInfo, formatCallstack(instance.callstack),
+		)
+	default:
+		// Any other verb formats the message, as fmt would for an error without Format
+		_, _ = fmt.Fprintf(state, fmt.FormatString(state, verb), instance.Error())
+	}
+}
+
 func (instance *StructuredError) Is(other error) (is bool) {
 	switch e := instance.cause.(type) {
 	case *StructuredError:
@@ -68,46 +154,78 @@ func (instance *StructuredError) As(other any) (ok bool) {
 }
 
 func (instance *StructuredError) getCallstack() (callstack string) {
+	originCallstack := instance.getOriginCallstack()
+	if len(originCallstack) == 0 {
+		// Only an enriched standard error has no callstack
+		return "unwrapped error - no callstack"
+	}
+
+	return strings.Join(formatCallstack(originCallstack), "\n")
+}
+
+// getOriginCallstack returns the callstack of the innermost layer, as that's the only one captured
+func (instance *StructuredError) getOriginCallstack() (callstack []uintptr) {
 	switch e := instance.cause.(type) {
 	case *StructuredError:
-		return e.getCallstack()
+		return e.getOriginCallstack()
 	default:
-		return strings.Join(instance.callstack, "\n")
+		return instance.callstack
 	}
 }
 
 func (instance *StructuredError) getAdditionalInfo(visitedContexts map[StructuredContext]bool) (additionalInfo AdditionalInfos) {
+	// The defaults come first, so every context and layer takes precedence over them. The snapshot is
+	// clipped, so appending copies it rather than writing into the shared defaults.
+	additionalInfo = slices.Clip(defaultInfo.snapshot())
+	instance.walkAdditionalInfo(visitedContexts, func(infos AdditionalInfos) {
+		additionalInfo = append(additionalInfo, infos...)
+	})
+
+	return additionalInfo
+}
+
+// mergeAdditionalInfo walks the chain once, merging every info into the ordered map, which is
+// equivalent to getAdditionalInfo(nil).Flatten() without building the intermediate slice
+func (instance *StructuredError) mergeAdditionalInfo(merged *orderedInfos) {
+	merged.setAll(defaultInfo.snapshot())
+	instance.walkAdditionalInfo(nil, merged.setAll)
+}
+
+func (instance *StructuredError) flattenAdditionalInfo() (additionalInfo AdditionalInfos) {
+	merged := getOrderedInfos()
+	defer putOrderedInfos(merged)
+
+	instance.mergeAdditionalInfo(merged)
+
+	return merged.toAdditionalInfos()
+}
+
+// walkAdditionalInfo visits the additional info of each layer from the outermost error inwards.
+// For each layer the context's info comes first, then the error's own info, and deeper in the chain
+// is more specific, so it is visited after the current layer.
+func (instance *StructuredError) walkAdditionalInfo(visitedContexts map[StructuredContext]bool, visit func(infos AdditionalInfos)) {
 	if visitedContexts == nil {
 		visitedContexts = make(map[StructuredContext]bool)
 	}
 
-	additionalInfo = AdditionalInfos{}
-
-	// If the context is not nil, we can extract the additional info from it
-	if instance.context != nil {
-		_, found := visitedContexts[instance.context]
-		if !found {
-			additionalInfo = append(instance.context.GetAdditionalInfo(), additionalInfo...)
-			visitedContexts[instance.context] = true
+	current := instance
+	for current != nil {
+		// If the context is not nil, we can extract the additional info from it
+		if current.context != nil {
+			_, found := visitedContexts[current.context]
+			if !found {
+				visit(current.context.GetAdditionalInfo())
+				visitedContexts[current.context] = true
+			}
 		}
-	}
 
-	// This error itself will have additional info, this should overwrite anything
-	// from the context, i.e. come later in the array
-	additionalInfo = append(additionalInfo, instance.additionalInfo...)
+		// This error itself will have additional info, this should overwrite anything
+		// from the context, i.e. come later
+		visit(current.additionalInfo)
 
-	// Finally recurse the errors, as each one may have more context and additional info
-	// and deeper in the chain is more specific, so they come after the current info
-	var childAdditionalInfo AdditionalInfos
-	switch e := instance.cause.(type) {
-	case *StructuredError:
-		childAdditionalInfo = e.getAdditionalInfo(visitedContexts)
-	default:
 		// Wrapped another type of error, don't traverse further
+		current, _ = current.cause.(*StructuredError)
 	}
-	additionalInfo = append(additionalInfo, childAdditionalInfo...)
-
-	return additionalInfo
 }
 
 // When logging to otel we will want each part separately, so we can transform to their types etc
@@ -116,7 +234,7 @@ func GetLoggingInfo(err error) (cause string, callstack string, additionalInfo A
 	case *StructuredError:
 		cause = e.Error()
 		callstack = e.getCallstack()
-		additionalInfo = e.getAdditionalInfo(nil).Flatten()
+		additionalInfo = e.flattenAdditionalInfo()
 	default:
 		cause = e.Error()
 		callstack = "unwrapped error - no callstack"
@@ -126,13 +244,37 @@ func GetLoggingInfo(err error) (cause string, callstack string, additionalInfo A
 	return cause, callstack, additionalInfo
 }
 
+// GetPropagatedInfo returns the flattened additional info of the error without the defaults, for
+// sending with the error to another service, which will log it with defaults of its own
+func GetPropagatedInfo(err error) (additionalInfo AdditionalInfos) {
+	structuredError, isStructured := err.(*StructuredError)
+	if !isStructured {
+		return make(AdditionalInfos, 0)
+	}
+
+	merged := getOrderedInfos()
+	defer putOrderedInfos(merged)
+
+	structuredError.walkAdditionalInfo(nil, merged.setAll)
+
+	return merged.toAdditionalInfos()
+}
+
 func PrintError(err error) (errString string) {
 	switch e := err.(type) {
 	case *StructuredError:
 		callstack := e.getCallstack()
-		additionalInfo := e.getAdditionalInfo(nil).ToJSON()
+		additionalInfo := e.flattenAdditionalInfo().ToJSON()
 
 		errString = fmt.Sprintf("Cause: %s\n", e.Error())
+		if e.id != "" {
+			errString += fmt.Sprintf("ID: %s\n", e.id)
+		}
+
+		origin := formatOrigin(e)
+		if origin != "" {
+			errString += fmt.Sprintf("Created: %s\n", origin)
+		}
 
 		// Sort additional info keys
 		sortedKeys := make([]string, 0, len(additionalInfo))
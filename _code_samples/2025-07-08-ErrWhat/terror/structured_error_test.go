@@ -265,6 +265,100 @@ func TestGetAdditionalInfo(t *testing.T) {
 	}
 }
 
+func TestFlattenAdditionalInfo(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+	}
+	type result struct {
+		additionalInfo AdditionalInfos
+	}
+	type testConfig struct {
+		name          string
+		instance      *StructuredError
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	sharedContext := &testContext{
+		additionalInfo: AdditionalInfos{WithStringInfo("key1", "context")},
+	}
+	configs := []testConfig{
+		{
+			name:     "returns an empty slice when there is no additional info",
+			instance: New(nil, fmt.Errorf("root error")),
+			args:     &args{},
+			result: &result{
+				additionalInfo: AdditionalInfos{},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name: "takes the deepest value of each key, retaining the order of the first instance",
+			instance: New(
+				&testContext{
+					additionalInfo: AdditionalInfos{WithStringInfo("key1", "outer context")},
+				},
+				New(nil, fmt.Errorf("root error"), WithStringInfo("key1", "inner"), WithStringInfo("key3", "inner")),
+				WithStringInfo("key2", "outer"),
+				WithStringInfo("key3", "outer"),
+			),
+			args: &args{},
+			result: &result{
+				additionalInfo: AdditionalInfos{
+					WithStringInfo("key1", "inner"),
+					WithStringInfo("key2", "outer"),
+					WithStringInfo("key3", "inner"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "only includes a shared context's additional info once",
+			instance: New(sharedContext, New(sharedContext, fmt.Errorf("root error"), WithStringInfo("key1", "inner")), WithStringInfo("key2", "outer")),
+			args:     &args{},
+			result: &result{
+				additionalInfo: AdditionalInfos{
+					WithStringInfo("key1", "inner"),
+					WithStringInfo("key2", "outer"),
+				},
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			additionalInfo := (*StructuredError).flattenAdditionalInfo(instance)
+
+			// Assert
+			assert.Equal(t, result.additionalInfo, additionalInfo)
+			assert.Equal(t, instance.getAdditionalInfo(nil).Flatten(), additionalInfo)
+
+			assertFunc(t)
+		})
+	}
+}
+
 func TestGetLoggingInfo(t *testing.T) {
 	t.Parallel()
 
@@ -370,7 +464,7 @@ func TestPrintError(t *testing.T) {
 			instance: New(nil, fmt.Errorf("root error"), WithStringInfo("key1", "value1")),
 			args:     &args{},
 			result: &result{
-				errString: "Cause: root error\nAdditional Info:\n\tkey1: value1\nCallstack:\n\t",
+				errString: "Cause: root error\nID: " + testErrorID + "\nAdditional Info:\n\tkey1: value1\nCallstack:\n\t",
 			},
 			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
 				return func(t *testing.T) {
@@ -417,6 +511,122 @@ func TestPrintError(t *testing.T) {
 	}
 }
 
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	type arrangeFunc func(t *testing.T)
+	type actFunc func(t *testing.T)
+	type assertFunc func(t *testing.T)
+	type args struct {
+		format string
+	}
+	type result struct {
+		formatted string
+	}
+	type testConfig struct {
+		name          string
+		instance      *StructuredError
+		args          *args
+		result        *result
+		generateHooks func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc)
+	}
+	configs := []testConfig{
+		{
+			name:     "formats the message with %v",
+			instance: New(nil, errors.New("root error"), WithStringInfo("key1", "value1")),
+			args: &args{
+				format: "%v",
+			},
+			result: &result{
+				formatted: "root error",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "formats the message with %s",
+			instance: New(nil, errors.New("root error"), WithStringInfo("key1", "value1")),
+			args: &args{
+				format: "%s",
+			},
+			result: &result{
+				formatted: "root error",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "formats the message with flags and width",
+			instance: New(nil, errors.New("root error"), WithStringInfo("key1", "value1")),
+			args: &args{
+				format: "[%14q]",
+			},
+			result: &result{
+				formatted: "[  \"root error\"]",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "formats as PrintError with %+v",
+			instance: New(nil, errors.New("root error"), WithStringInfo("key1", "value1")),
+			args: &args{
+				format: "%+v",
+			},
+			result: &result{
+				formatted: "Cause: root error\nID: " + testErrorID + "\nAdditional Info:\n\tkey1: value1\nCallstack:\n\t",
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {
+					// Find the current file path
+					_, currentFilePath, _, ok := runtime.Caller(1)
+					assert.True(t, ok)
+
+					result.formatted += currentFilePath
+				}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+		{
+			name:     "formats as go syntax with %#v",
+			instance: New(nil, errors.New("root error"), WithStringInfo("key1", "value1")),
+			args: &args{
+				format: "%#v",
+			},
+			result: &result{
+				formatted: `&terror.StructuredError{cause:&errors.errorString{s:"root error"}, additionalInfo:terror.AdditionalInfos{terror.typedInfo[string]{key:"key1", value:"value1"}}, callstack:[]string{"`,
+			},
+			generateHooks: func(t *testing.T, args *args, result *result) (arrangeFunc arrangeFunc, actFunc actFunc, assertFunc assertFunc) {
+				return func(t *testing.T) {}, func(t *testing.T) {}, func(t *testing.T) {}
+			},
+		},
+	}
+
+	for _, config := range configs {
+		t.Run(config.name, func(t *testing.T) {
+			// Arrange
+			instance := config.instance
+			args := config.args
+			result := config.result
+			arrangeFunc, actFunc, assertFunc := config.generateHooks(t, args, result)
+
+			arrangeFunc(t)
+
+			// Act
+			actFunc(t)
+			formatted := fmt.Sprintf(args.format, instance)
+
+			// Assert
+			assert.Contains(t, formatted, result.formatted)
+			assert.NotContains(t, formatted, "%!")
+
+			assertFunc(t)
+		})
+	}
+}
+
 func TestSupportsErrorsPackage(t *testing.T) {
 	t.Parallel()
 
@@ -481,3 +691,31 @@ func TestSupportsErrorsPackage(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkGetLoggingInfo(b *testing.B) {
+	for _, depth := range []int{1, 8, 64} {
+		for _, infoCount := range []int{1, 8, 64} {
+			b.Run(fmt.Sprintf("depth=%d/infos=%d", depth, infoCount), func(b *testing.B) {
+				var err error = fmt.Errorf("root error")
+				for layer := range depth {
+					ctx := &testContext{
+						additionalInfo: make(AdditionalInfos, 0, infoCount),
+					}
+					infos := make(AdditionalInfos, 0, infoCount)
+					for i := range infoCount {
+						// Half the keys are shared across layers, so merging has work to do
+						ctx.additionalInfo = append(ctx.additionalInfo, WithIntInfo(fmt.Sprintf("context%d", i%(infoCount/2+1)), layer))
+						infos = append(infos, WithIntInfo(fmt.Sprintf("key%d", i%(infoCount/2+1)+layer%2*infoCount), i))
+					}
+					err = New(ctx, err, infos...)
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for b.Loop() {
+					_, _, _ = GetLoggingInfo(err)
+				}
+			})
+		}
+	}
+}